@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"os"
 	"sync"
+
+	"github.com/fsnotify/fsnotify"
 )
 
 // App struct
@@ -17,6 +20,20 @@ type App struct {
 	// Environment config caching
 	envConfig   *EnvConfig
 	envConfigMu sync.RWMutex
+	// envConfigDir is the workspace the cached config was loaded for
+	envConfigDir string
+	// envWriteMu serializes updates of env.json
+	envWriteMu sync.Mutex
+
+	// Watching the env.json files for changes made outside the app
+	envWatcher    *fsnotify.Watcher
+	envWatchFiles map[string]bool
+	envFileHashes map[string][sha256.Size]byte
+	envWatchMu    sync.Mutex
+
+	// vaultKey unlocks the secrets vault for the session, nil while locked
+	vaultKey []byte
+	vaultMu  sync.Mutex
 }
 
 // NewApp creates a new App application struct
@@ -37,6 +54,8 @@ func (a *App) startup(ctx context.Context) {
 	} else {
 		a.currentDir = homeDir
 	}
+
+	a.startEnvWatcher()
 }
 
 // Greet returns a greeting for the given name
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// CompletionItem is a suggestion for the editor. StartColumn is the 1-based
+// column where the text replaced by InsertText starts, the cursor is the end.
+// Kind is one of variable, section, header, value, query, filter, predicate or file.
+type CompletionItem struct {
+	Label       string `json:"label"`
+	Kind        string `json:"kind"`
+	Detail      string `json:"detail,omitempty"`
+	InsertText  string `json:"insertText"`
+	StartColumn int    `json:"startColumn"`
+}
+
+// commonHeaders are suggested for request headers, with their usual values
+var commonHeaders = map[string][]string{
+	"Accept":            {"application/json", "application/xml", "text/html", "text/plain", "*/*"},
+	"Accept-Encoding":   {"gzip, deflate, br", "identity"},
+	"Accept-Language":   {"en-US,en;q=0.9"},
+	"Authorization":     {"Bearer ", "Basic "},
+	"Cache-Control":     {"no-cache", "no-store", "max-age=0"},
+	"Connection":        {"keep-alive", "close"},
+	"Content-Type":      {"application/json", "application/x-www-form-urlencoded", "multipart/form-data", "application/xml", "text/plain"},
+	"Cookie":            nil,
+	"If-Match":          nil,
+	"If-None-Match":     nil,
+	"Origin":            nil,
+	"Referer":           nil,
+	"User-Agent":        {"hurl"},
+	"X-Api-Key":         nil,
+	"X-Request-Id":      {"{{newUuid}}"},
+	"X-Requested-With":  {"XMLHttpRequest"},
+	"X-Forwarded-For":   nil,
+	"If-Modified-Since": nil,
+}
+
+var (
+	completionSectionRegex  = regexp.MustCompile(`^\s*\[(\w*)$`)
+	completionTemplateRegex = regexp.MustCompile(`\{\{\s*([^{}\s]*)$`)
+	completionFileRegex     = regexp.MustCompile(`file,\s*([^;]*)$`)
+)
+
+// GetCompletions returns the suggestions at a 1-based line and column of a hurl file
+func (a *App) GetCompletions(path string, line int, column int) ([]CompletionItem, error) {
+	content, err := a.GetFileContent(path)
+	if err != nil {
+		return nil, err
+	}
+	return a.completions(path, content, line, column), nil
+}
+
+// GetCompletionsForContent returns the suggestions for unsaved content of the hurl file at path
+func (a *App) GetCompletionsForContent(path string, content string, line int, column int) []CompletionItem {
+	return a.completions(path, content, line, column)
+}
+
+func (a *App) completions(path string, content string, line int, column int) []CompletionItem {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if line < 1 || line > len(lines) {
+		return []CompletionItem{}
+	}
+	text := []rune(lines[line-1])
+	if column < 1 {
+		column = 1
+	}
+	if column-1 > len(text) {
+		column = len(text) + 1
+	}
+	prefix := string(text[:column-1])
+	trimmed := strings.TrimLeft(prefix, " \t")
+
+	if m := completionTemplateRegex.FindStringSubmatch(prefix); m != nil {
+		suffix := string(text[column-1:])
+		return a.variableCompletions(parseHurl(content), line, column-len([]rune(m[1])), !strings.HasPrefix(strings.TrimLeft(suffix, " "), "}}"))
+	}
+
+	// Comments get no suggestions
+	if strings.HasPrefix(trimmed, "#") {
+		return []CompletionItem{}
+	}
+
+	if m := completionFileRegex.FindStringSubmatch(prefix); m != nil {
+		return fileCompletions(filepath.Dir(path), m[1], column)
+	}
+
+	part, section := completionContext(lines, line)
+
+	if m := completionSectionRegex.FindStringSubmatch(prefix); m != nil {
+		return sectionCompletions(part, column-len([]rune(m[1])))
+	}
+
+	tokens, _ := tokenizeHurl(trimmed)
+	// The token under the cursor is still being typed
+	typing := ""
+	if len(tokens) > 0 && !strings.HasSuffix(trimmed, " ") {
+		typing = tokens[len(tokens)-1]
+		tokens = tokens[:len(tokens)-1]
+	}
+	start := column - len([]rune(typing))
+
+	switch {
+	case section == "Asserts":
+		return queryCompletions(tokens, start, true)
+	case section == "Captures":
+		colon := strings.Index(trimmed, ":")
+		if colon < 0 {
+			return []CompletionItem{}
+		}
+		valueTokens, _ := tokenizeHurl(trimmed[colon+1:])
+		if typing != "" && len(valueTokens) > 0 && !strings.HasSuffix(trimmed, " ") {
+			valueTokens = valueTokens[:len(valueTokens)-1]
+		}
+		return queryCompletions(valueTokens, start, false)
+	case section == "" && part == "request":
+		return headerCompletions(trimmed, column)
+	}
+
+	return []CompletionItem{}
+}
+
+// completionContext returns whether a line is in the request or response of
+// its entry, and the section it belongs to. Lines are scanned upwards so the
+// context is found even while the file does not parse.
+func completionContext(lines []string, line int) (string, string) {
+	section := ""
+	for i := line - 2; i >= 0; i-- {
+		text := strings.TrimSpace(stripHurlComment(lines[i]))
+		if m := hurlSectionLineRegex.FindStringSubmatch(text); m != nil && section == "" {
+			section = m[1]
+			continue
+		}
+		if hurlStatusRegex.MatchString(text) {
+			return "response", section
+		}
+		if isHurlEntryLine(text) {
+			return "request", section
+		}
+	}
+	return "", section
+}
+
+// variableCompletions suggests environment variables, generators and the
+// variables defined before the line
+func (a *App) variableCompletions(file *HurlFile, line int, start int, closeTemplate bool) []CompletionItem {
+	items := []CompletionItem{}
+	seen := make(map[string]bool)
+	add := func(name string, detail string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		insert := name
+		if closeTemplate {
+			insert += "}}"
+		}
+		items = append(items, CompletionItem{Label: name, Kind: "variable", Detail: detail, InsertText: insert, StartColumn: start})
+	}
+
+	if vars, err := a.activeVariables(); err == nil {
+		for _, name := range sortedKeys(vars) {
+			add(name, vars[name])
+		}
+	}
+
+	for _, d := range hurlDefinitions(file) {
+		if d.Span.Start.Line >= line {
+			continue
+		}
+		if d.Option {
+			add(d.Name, fmt.Sprintf("option of entry %d", d.Entry))
+		} else {
+			add(d.Name, fmt.Sprintf("captured in entry %d", d.Entry))
+		}
+	}
+
+	for _, name := range []string{"newUuid", "newDate"} {
+		add(name, "generated by hurl")
+	}
+	return items
+}
+
+// sectionCompletions suggests the sections allowed in a part of an entry
+func sectionCompletions(part string, start int) []CompletionItem {
+	allowed := hurlRequestSections
+	if part == "response" {
+		allowed = hurlResponseSections
+	}
+
+	items := []CompletionItem{}
+	for _, name := range hurlSectionOrder {
+		if allowed[name] {
+			items = append(items, CompletionItem{Label: "[" + name + "]", Kind: "section", InsertText: name + "]", StartColumn: start})
+		}
+	}
+	return items
+}
+
+// queryCompletions suggests query types, then filters and predicates for the
+// tokens already written. Predicates are only offered in asserts.
+func queryCompletions(tokens []string, start int, withPredicates bool) []CompletionItem {
+	items := []CompletionItem{}
+
+	if len(tokens) == 0 {
+		for _, name := range slices.Sorted(maps.Keys(hurlQueryArgs)) {
+			insert := name
+			if hurlQueryArgs[name] {
+				insert += ` ""`
+			}
+			items = append(items, CompletionItem{Label: name, Kind: "query", InsertText: insert, StartColumn: start})
+		}
+		return items
+	}
+
+	// Nothing is suggested while a query or filter still waits for its argument
+	query, next, err := parseHurlQuery(tokens, 0)
+	if err != nil {
+		return items
+	}
+	_, next, err = parseHurlFilters(tokens, next)
+	if err != nil || query.Type == "" {
+		return items
+	}
+
+	rest := tokens[next:]
+	if len(rest) == 0 {
+		for _, name := range slices.Sorted(maps.Keys(hurlFilterArgs)) {
+			items = append(items, CompletionItem{Label: name, Kind: "filter", InsertText: name, StartColumn: start})
+		}
+	}
+	if !withPredicates {
+		return items
+	}
+	if len(rest) == 0 || len(rest) == 1 && rest[0] == "not" {
+		if len(rest) == 0 {
+			items = append(items, CompletionItem{Label: "not", Kind: "predicate", InsertText: "not", StartColumn: start})
+		}
+		for _, name := range slices.Sorted(maps.Keys(hurlPredicateArgs)) {
+			items = append(items, CompletionItem{Label: name, Kind: "predicate", InsertText: name, StartColumn: start})
+		}
+	}
+	return items
+}
+
+// headerCompletions suggests header names, and values once the colon is written
+func headerCompletions(trimmed string, column int) []CompletionItem {
+	items := []CompletionItem{}
+
+	colon := strings.Index(trimmed, ":")
+	if colon < 0 {
+		start := column - len([]rune(trimmed))
+		for _, name := range slices.Sorted(maps.Keys(commonHeaders)) {
+			items = append(items, CompletionItem{Label: name, Kind: "header", InsertText: name + ": ", StartColumn: start})
+		}
+		return items
+	}
+
+	name := strings.TrimSpace(trimmed[:colon])
+	typed := strings.TrimLeft(trimmed[colon+1:], " ")
+	start := column - len([]rune(typed))
+	for key, values := range commonHeaders {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		for _, value := range values {
+			items = append(items, CompletionItem{Label: value, Kind: "value", InsertText: value, StartColumn: start})
+		}
+	}
+	return items
+}
+
+// fileCompletions lists the files matching a path typed after file, relative to dir
+func fileCompletions(dir string, typed string, column int) []CompletionItem {
+	items := []CompletionItem{}
+
+	typedDir, partial := "", typed
+	if i := strings.LastIndex(typed, "/"); i >= 0 {
+		typedDir, partial = typed[:i+1], typed[i+1:]
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, filepath.FromSlash(typedDir)))
+	if err != nil {
+		return items
+	}
+
+	start := column - len([]rune(partial))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(partial, ".") {
+			continue
+		}
+		if entry.IsDir() {
+			items = append(items, CompletionItem{Label: name + "/", Kind: "file", Detail: "directory", InsertText: name + "/", StartColumn: start})
+			continue
+		}
+		items = append(items, CompletionItem{Label: name, Kind: "file", InsertText: name + ";", StartColumn: start})
+	}
+	return items
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// dotenvVariable is one assignment of a .env file
+type dotenvVariable struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+var (
+	dotenvKeyRegex       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+	dotenvCommentRegex   = regexp.MustCompile(`\s#`)
+	dotenvPlainRegex     = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+	dotenvReferenceRegex = regexp.MustCompile(`^` + osVariableRegex.String())
+	dotenvTemplateRegex  = regexp.MustCompile(`^` + templateRegex.String())
+)
+
+// parseDotenv reads the assignments of a .env file. Blank lines, # comments
+// and export prefixes are skipped. Single-quoted values are taken literally,
+// double-quoted values may span lines and use \n, \r, \t, \", \\ and \$
+// escapes, unquoted values end at a # preceded by whitespace. ${NAME}
+// references to a key of the same file become {{NAME}} references, NAME
+// sanitized the way the key is when it is imported, others are kept and
+// resolved from the OS environment. Values are returned the way
+// env.json holds them, with the text resolveVariables must not expand
+// escaped.
+func parseDotenv(content string) ([]dotenvVariable, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var variables []dotenvVariable
+	quotes := make(map[int]byte)
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimSpace(rest)
+		}
+
+		key, after, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !dotenvKeyRegex.MatchString(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNumber)
+		}
+
+		value := strings.TrimLeft(after, " \t")
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			if loc := dotenvCommentRegex.FindStringIndex(after); loc != nil {
+				after = after[:loc[0]]
+			}
+			variables = append(variables, dotenvVariable{Key: key, Value: strings.TrimSpace(after), Line: lineNumber})
+			continue
+		}
+
+		// Quoted values run to the closing quote, on this line or a later one
+		quote := value[0]
+		value = value[1:]
+		end := closingQuote(value, quote)
+		for end < 0 && i+1 < len(lines) {
+			i++
+			value += "\n" + lines[i]
+			end = closingQuote(value, quote)
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("line %d: unterminated quoted value", lineNumber)
+		}
+		if trailing := strings.TrimSpace(value[end+1:]); trailing != "" && !strings.HasPrefix(trailing, "#") {
+			return nil, fmt.Errorf("line %d: unexpected text after the closing quote", lineNumber)
+		}
+
+		quotes[len(variables)] = quote
+		variables = append(variables, dotenvVariable{Key: key, Value: value[:end], Line: lineNumber})
+	}
+
+	keys := make(map[string]string)
+	for _, v := range variables {
+		keys[v.Key] = sanitizeVariableName(v.Key)
+	}
+	for i := range variables {
+		if quotes[i] == '\'' {
+			variables[i].Value = escapeReferences(variables[i].Value)
+		} else {
+			variables[i].Value = convertDotenvValue(variables[i].Value, quotes[i] == '"', keys)
+		}
+	}
+
+	return variables, nil
+}
+
+// closingQuote returns the index of the quote ending value, -1 if there is none
+func closingQuote(value string, quote byte) int {
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// convertDotenvValue turns an unquoted or double-quoted value into an env.json
+// value. ${NAME} references to keys become references to the variable keys
+// maps them to, {{name}} has no meaning in a .env file and is escaped, and so
+// are the ${NAME} escaped by \$ in a double-quoted value, whose escapes are
+// replaced.
+func convertDotenvValue(value string, quoted bool, keys map[string]string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		rest := value[i:]
+		if m := dotenvReferenceRegex.FindStringSubmatchIndex(rest); m != nil {
+			if name, ok := keys[rest[m[2]:m[3]]]; ok {
+				b.WriteString("{{" + name + "}}")
+			} else {
+				b.WriteString(rest[:m[1]])
+			}
+			i += m[1] - 1
+			continue
+		}
+		if loc := dotenvTemplateRegex.FindStringIndex(rest); loc != nil {
+			b.WriteString(`\` + rest[:loc[1]])
+			i += loc[1] - 1
+			continue
+		}
+		if !quoted || value[i] != '\\' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+
+		i++
+		switch value[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '$':
+			if loc := dotenvReferenceRegex.FindStringIndex(value[i:]); loc != nil {
+				b.WriteString(`\` + value[i:i+loc[1]])
+				i += loc[1] - 1
+			} else {
+				b.WriteByte('$')
+			}
+		case '"', '\\':
+			b.WriteByte(value[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// ImportDotenv merges .env files into env.json. files maps each environment
+// to the .env file to import into it, environments are created if needed.
+func (a *App) ImportDotenv(files map[string]string) (*ImportResult, error) {
+	result := &ImportResult{}
+	imported := make(map[string][]dotenvVariable)
+	for _, env := range slices.Sorted(maps.Keys(files)) {
+		if err := validateName("environment", env); err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(files[env])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", files[env], err)
+		}
+		variables, err := parseDotenv(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid .env file %s: %w", files[env], err)
+		}
+
+		// Secrets keep their value, the vault is only changed through SetSecret
+		var kept []dotenvVariable
+		for _, v := range variables {
+			if key := sanitizeVariableName(v.Key); key != v.Key {
+				result.warnf("%s line %d: variable %s was renamed to %s", files[env], v.Line, v.Key, key)
+				v.Key = key
+			}
+			if secret, err := isSecret(env, v.Key); err != nil {
+				return nil, err
+			} else if secret {
+				result.warnf("%s line %d: %s is a secret of %s, its value was not imported", files[env], v.Line, v.Key, env)
+				continue
+			}
+			kept = append(kept, v)
+		}
+		imported[env] = kept
+		result.Environments = append(result.Environments, env)
+	}
+
+	err := a.updateEnvConfig(func(config *EnvConfig) error {
+		for env, variables := range imported {
+			vars, ok := config.Environments[env]
+			if !ok {
+				vars = make(map[string]string)
+				config.Environments[env] = vars
+			}
+			for _, v := range variables {
+				vars[v.Key] = v.Value
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExportEnvironment returns the variables of an environment, merged with the
+// global variables and the environments it extends and resolved, as a .env
+// file ("dotenv") or as hurl --variable arguments ("args"). Secrets and the
+// variables built from them are left out.
+func (a *App) ExportEnvironment(environment string, format string) (string, error) {
+	raw, err := a.rawVariables(environment)
+	if err != nil {
+		return "", err
+	}
+	resolved, refs, err := resolveVariables(raw)
+	if err != nil {
+		return "", err
+	}
+	config, err := a.loadEnvConfig()
+	if err != nil {
+		return "", err
+	}
+
+	var secrets, names []string
+	for _, name := range slices.Sorted(maps.Keys(resolved)) {
+		secret := raw[name] == secretMask
+		for ref := range refs[name] {
+			secret = secret || raw[ref] == secretMask
+		}
+		if secret {
+			secrets = append(secrets, name)
+		} else {
+			names = append(names, name)
+		}
+	}
+
+	switch format {
+	case "dotenv":
+		var b strings.Builder
+		for _, name := range secrets {
+			fmt.Fprintf(&b, "# %s is a secret and was not exported\n", name)
+		}
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s=%s\n", name, dotenvValue(resolved[name]))
+		}
+		return b.String(), nil
+	case "args":
+		args := make([]string, 0, len(names))
+		for _, name := range names {
+			literal, err := variableLiteral(name, config.Types[name], resolved[name])
+			if err != nil {
+				return "", err
+			}
+			args = append(args, "--variable "+shellQuote(name+"="+literal))
+		}
+		return strings.Join(args, " \\\n"), nil
+	default:
+		return "", fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// dotenvValue quotes a value for a .env file when it is not plain text
+func dotenvValue(value string) string {
+	if dotenvPlainRegex.MatchString(value) {
+		return value
+	}
+	if !strings.ContainsAny(value, "'\r\n") {
+		return "'" + value + "'"
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "$", `\$`)
+	return `"` + escaper.Replace(value) + `"`
+}
+
+// shellQuote quotes an argument for a POSIX shell
+func shellQuote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []dotenvVariable
+		err     string
+	}{
+		{
+			name:    "reference to a key",
+			content: "HOST=example.com\nURL=https://${HOST}/api\n",
+			want:    []dotenvVariable{{Key: "HOST", Value: "example.com", Line: 1}, {Key: "URL", Value: "https://{{HOST}}/api", Line: 2}},
+		},
+		{
+			name:    "reference to a key that is renamed",
+			content: "_TOKEN=abc\nexport AUTH=\"Bearer ${_TOKEN}\"\n",
+			want:    []dotenvVariable{{Key: "_TOKEN", Value: "abc", Line: 1}, {Key: "AUTH", Value: "Bearer {{v_TOKEN}}", Line: 2}},
+		},
+		{
+			name:    "reference to the OS environment",
+			content: "DIR=${HOME}/data # comment\n",
+			want:    []dotenvVariable{{Key: "DIR", Value: "${HOME}/data", Line: 1}},
+		},
+		{
+			name:    "double-quoted escapes",
+			content: "MSG=\"a\\tb\nc\"\n",
+			want:    []dotenvVariable{{Key: "MSG", Value: "a\tb\nc", Line: 1}},
+		},
+		{
+			name:    "missing assignment",
+			content: "# comment\nNOPE\n",
+			err:     "line 2: expected KEY=value",
+		},
+		{
+			name:    "unterminated quote",
+			content: "A='abc\n",
+			err:     "line 1: unterminated quoted value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDotenv(tt.content)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseDotenv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// disabledPrefix marks the lines of a disabled entry, so they can be told
+// apart from ordinary comments and enabled again
+const disabledPrefix = "#~"
+
+// entryBlock is the 0-based, inclusive line range of an entry together with
+// the comments directly above its request line
+type entryBlock struct {
+	start int
+	end   int
+}
+
+// entryBlocks returns the line range of every entry. Comments belong to an
+// entry when no blank line separates them from the request line.
+func entryBlocks(lines []string, file *HurlFile) []entryBlock {
+	blocks := make([]entryBlock, 0, len(file.Entries))
+	for _, entry := range file.Entries {
+		start := entry.Span.Start.Line - 1
+		for start > 0 {
+			above := strings.TrimSpace(lines[start-1])
+			if !strings.HasPrefix(above, "#") || strings.HasPrefix(above, disabledPrefix) {
+				break
+			}
+			start--
+		}
+		blocks = append(blocks, entryBlock{start: start, end: entry.Span.End.Line - 1})
+	}
+	return blocks
+}
+
+// editEntries applies an edit to the lines of a hurl file and saves the result.
+// Files with syntax errors are not edited, their entries cannot be trusted.
+func (a *App) editEntries(path string, index int, edit func(lines []string, blocks []entryBlock, i int) ([]string, error)) error {
+	content, err := a.GetFileContent(path)
+	if err != nil {
+		return err
+	}
+
+	file := parseHurl(content)
+	if len(file.Errors) > 0 {
+		e := file.Errors[0]
+		return fmt.Errorf("cannot edit %s, line %d: %s", path, e.Span.Start.Line, e.Message)
+	}
+	if index < 1 || index > len(file.Entries) {
+		return fmt.Errorf("entry %d does not exist, %s has %d entries", index, path, len(file.Entries))
+	}
+
+	lines := strings.Split(content, "\n")
+	edited, err := edit(lines, entryBlocks(lines, file), index-1)
+	if err != nil {
+		return err
+	}
+	return a.SaveFile(path, strings.Join(edited, "\n"))
+}
+
+// DuplicateEntry inserts a copy of an entry right after it. index is 1-based.
+func (a *App) DuplicateEntry(path string, index int) error {
+	return a.editEntries(path, index, func(lines []string, blocks []entryBlock, i int) ([]string, error) {
+		b := blocks[i]
+		edited := append([]string{}, lines[:b.end+1]...)
+		edited = append(edited, "")
+		edited = append(edited, lines[b.start:b.end+1]...)
+		return append(edited, lines[b.end+1:]...), nil
+	})
+}
+
+// MoveEntryUp swaps an entry with the one before it. index is 1-based.
+func (a *App) MoveEntryUp(path string, index int) error {
+	return a.editEntries(path, index, func(lines []string, blocks []entryBlock, i int) ([]string, error) {
+		if i == 0 {
+			return nil, fmt.Errorf("entry %d is already the first entry", index)
+		}
+		return swapBlocks(lines, blocks[i-1], blocks[i]), nil
+	})
+}
+
+// MoveEntryDown swaps an entry with the one after it. index is 1-based.
+func (a *App) MoveEntryDown(path string, index int) error {
+	return a.editEntries(path, index, func(lines []string, blocks []entryBlock, i int) ([]string, error) {
+		if i == len(blocks)-1 {
+			return nil, fmt.Errorf("entry %d is already the last entry", index)
+		}
+		return swapBlocks(lines, blocks[i], blocks[i+1]), nil
+	})
+}
+
+// swapBlocks exchanges two blocks, keeping the lines between them in place
+func swapBlocks(lines []string, first entryBlock, second entryBlock) []string {
+	edited := append([]string{}, lines[:first.start]...)
+	edited = append(edited, lines[second.start:second.end+1]...)
+	edited = append(edited, lines[first.end+1:second.start]...)
+	edited = append(edited, lines[first.start:first.end+1]...)
+	return append(edited, lines[second.end+1:]...)
+}
+
+// ExtractEntry moves an entry to a new hurl file at newPath. index is 1-based.
+// Variables the entry captured from earlier entries are not carried over.
+func (a *App) ExtractEntry(path string, index int, newPath string) error {
+	if newPath == "" {
+		return fmt.Errorf("file path cannot be empty")
+	}
+	if !strings.HasSuffix(strings.ToLower(newPath), ".hurl") {
+		return fmt.Errorf("%s is not a .hurl file", newPath)
+	}
+
+	return a.editEntries(path, index, func(lines []string, blocks []entryBlock, i int) ([]string, error) {
+		b := blocks[i]
+
+		f, err := os.OpenFile(newPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			if os.IsExist(err) {
+				return nil, fmt.Errorf("file %s already exists", newPath)
+			}
+			return nil, fmt.Errorf("failed to create file %s: %w", newPath, err)
+		}
+		f.Close()
+
+		extracted := strings.Join(lines[b.start:b.end+1], "\n") + "\n"
+		if err := a.SaveFile(newPath, extracted); err != nil {
+			os.Remove(newPath)
+			return nil, err
+		}
+		return removeBlock(lines, b), nil
+	})
+}
+
+// removeBlock deletes a block and the blank lines left doubled by its removal
+func removeBlock(lines []string, b entryBlock) []string {
+	edited := append([]string{}, lines[:b.start]...)
+	rest := lines[b.end+1:]
+	for len(rest) > 1 && strings.TrimSpace(rest[0]) == "" &&
+		(len(edited) == 0 || strings.TrimSpace(edited[len(edited)-1]) == "") {
+		rest = rest[1:]
+	}
+	return append(edited, rest...)
+}
+
+// DisableEntry comments out an entry with the #~ prefix so hurl skips it.
+// index is 1-based. EnableEntry restores it. A blank line is kept between
+// disabled entries, it is the only boundary between them.
+func (a *App) DisableEntry(path string, index int) error {
+	return a.editEntries(path, index, func(lines []string, blocks []entryBlock, i int) ([]string, error) {
+		b := blocks[i]
+		edited := append([]string{}, lines[:b.start]...)
+		if b.start > 0 && strings.HasPrefix(lines[b.start-1], disabledPrefix) {
+			edited = append(edited, "")
+		}
+		for n := b.start; n <= b.end; n++ {
+			if lines[n] == "" {
+				edited = append(edited, disabledPrefix)
+			} else {
+				edited = append(edited, disabledPrefix+" "+lines[n])
+			}
+		}
+		if b.end+1 < len(lines) && strings.HasPrefix(lines[b.end+1], disabledPrefix) {
+			edited = append(edited, "")
+		}
+		return append(edited, lines[b.end+1:]...), nil
+	})
+}
+
+// EnableEntry restores the disabled entry that contains a 1-based line
+func (a *App) EnableEntry(path string, line int) error {
+	content, err := a.GetFileContent(path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(content, "\n")
+	if line < 1 || line > len(lines) || !strings.HasPrefix(lines[line-1], disabledPrefix) {
+		return fmt.Errorf("line %d of %s is not part of a disabled entry", line, path)
+	}
+
+	start, end := line-1, line-1
+	for start > 0 && strings.HasPrefix(lines[start-1], disabledPrefix) {
+		start--
+	}
+	for end < len(lines)-1 && strings.HasPrefix(lines[end+1], disabledPrefix) {
+		end++
+	}
+
+	for n := start; n <= end; n++ {
+		text := strings.TrimPrefix(lines[n], disabledPrefix)
+		lines[n] = strings.TrimPrefix(text, " ")
+	}
+	return a.SaveFile(path, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDisableEntry(t *testing.T) {
+	content := "GET https://a\nHTTP 200\n# second\nGET https://b\nHTTP 200\n"
+
+	tests := []struct {
+		name    string
+		disable []int
+		enable  int
+		want    string
+		enabled string
+	}{
+		{
+			name:    "first entry",
+			disable: []int{1},
+			enable:  1,
+			want:    "#~ GET https://a\n#~ HTTP 200\n# second\nGET https://b\nHTTP 200\n",
+			enabled: content,
+		},
+		{
+			name:    "adjacent entries, first one first",
+			disable: []int{1, 1},
+			enable:  1,
+			want:    "#~ GET https://a\n#~ HTTP 200\n\n#~ # second\n#~ GET https://b\n#~ HTTP 200\n",
+			enabled: "GET https://a\nHTTP 200\n\n#~ # second\n#~ GET https://b\n#~ HTTP 200\n",
+		},
+		{
+			name:    "adjacent entries, second one first",
+			disable: []int{2, 1},
+			enable:  4,
+			want:    "#~ GET https://a\n#~ HTTP 200\n\n#~ # second\n#~ GET https://b\n#~ HTTP 200\n",
+			enabled: "#~ GET https://a\n#~ HTTP 200\n\n# second\nGET https://b\nHTTP 200\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "entries.hurl")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			a := NewApp()
+			for _, index := range tt.disable {
+				if err := a.DisableEntry(path, index); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.want {
+				t.Fatalf("disabled = %q, want %q", got, tt.want)
+			}
+
+			if err := a.EnableEntry(path, tt.enable); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.enabled {
+				t.Errorf("enabled = %q, want %q", got, tt.enabled)
+			}
+		})
+	}
+}
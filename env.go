@@ -5,14 +5,23 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 )
 
 // EnvConfig represents the environment variables configuration
 type EnvConfig struct {
-	Version             string                       `json:"version"`
-	ActiveEnvironment   string                       `json:"activeEnvironment"`
+	Schema              string                       `json:"$schema,omitempty"`
+	Version             string                       `json:"version,omitempty"`
+	ActiveEnvironment   string                       `json:"activeEnvironment,omitempty"`
 	Global              map[string]string            `json:"global"`
 	Environments        map[string]map[string]string `json:"environments"`
+	// Extends maps an environment to the environment it inherits variables from
+	Extends             map[string]string            `json:"extends,omitempty"`
+	// Types maps a variable to its type in every environment, variables without a type are passed as written
+	Types               map[string]string            `json:"types,omitempty"`
+	// Protected lists the environments whose runs need confirmation
+	Protected           map[string]EnvironmentProtection `json:"protected,omitempty"`
 }
 
 // getEnvFilePath returns the path to the env.json file
@@ -40,11 +49,17 @@ func (a *App) LoadEnvVariables() (string, error) {
 		return "", err
 	}
 
+	// Editors validate env.json against the schema written next to it
+	if err := writeEnvSchema(filepath.Dir(envFilePath)); err != nil {
+		fmt.Printf("Error writing env.json schema: %v\n", err)
+	}
+
 	// Check if file exists
 	if _, err := os.Stat(envFilePath); os.IsNotExist(err) {
 		// Create default config
 		defaultConfig := EnvConfig{
-			Version:           "1.0",
+			Schema:            "./" + envSchemaFile,
+			Version:           currentEnvVersion,
 			ActiveEnvironment: "development",
 			Global: map[string]string{
 				"timeout":     "30",
@@ -82,14 +97,24 @@ func (a *App) LoadEnvVariables() (string, error) {
 		return "", fmt.Errorf("failed to read env.json: %w", err)
 	}
 
-	return string(data), nil
+	// Files written by older versions are upgraded once, on disk
+	data, err = a.migrateEnvFile(envFilePath, data)
+	if err != nil {
+		return "", err
+	}
+
+	// Secrets are listed with a masked value, never in clear
+	return maskSecrets(data)
 }
 
 // loadEnvConfig loads and caches the environment config
 func (a *App) loadEnvConfig() (*EnvConfig, error) {
+	// The config is cached per workspace, not per browsed folder
+	workspace := workspaceRoot(a.currentDir)
+
 	// Check cache first (read lock)
 	a.envConfigMu.RLock()
-	if a.envConfig != nil {
+	if a.envConfig != nil && a.envConfigDir == workspace {
 		defer a.envConfigMu.RUnlock()
 		return a.envConfig, nil
 	}
@@ -100,22 +125,30 @@ func (a *App) loadEnvConfig() (*EnvConfig, error) {
 	defer a.envConfigMu.Unlock()
 
 	// Double-check after acquiring write lock
-	if a.envConfig != nil {
+	if a.envConfig != nil && a.envConfigDir == workspace {
 		return a.envConfig, nil
 	}
 
-	// Load from file
-	configJSON, err := a.LoadEnvVariables()
+	// Load from the user file, layered over the project file of the workspace
+	layers, err := a.envLayers()
 	if err != nil {
 		return nil, err
 	}
 
-	var config EnvConfig
-	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
-		return nil, fmt.Errorf("failed to parse env.json: %w", err)
+	config := layers[0].Config
+	for _, layer := range layers[1:] {
+		config = mergeEnvConfigs(layer.Config, config)
 	}
 
-	a.envConfig = &config
+	a.envConfig = config
+	a.envConfigDir = workspace
+
+	// Follow external changes to the files the config was loaded from
+	paths := make([]string, 0, len(layers))
+	for _, layer := range layers {
+		paths = append(paths, layer.Path)
+	}
+	a.watchEnvFiles(paths)
 	return a.envConfig, nil
 }
 
@@ -128,16 +161,33 @@ func (a *App) invalidateEnvCache() {
 
 // SaveEnvVariables saves the environment variables to env.json
 func (a *App) SaveEnvVariables(content string) error {
+	// Content in an older format is upgraded first
+	data, _, _, err := migrateEnvDocument([]byte(content))
+	if err != nil {
+		return err
+	}
+
 	// Validate JSON structure first
 	var config EnvConfig
-	if err := json.Unmarshal([]byte(content), &config); err != nil {
+	if err := json.Unmarshal(data, &config); err != nil {
 		return fmt.Errorf("invalid JSON format: %w", err)
 	}
 
+	a.envWriteMu.Lock()
+	defer a.envWriteMu.Unlock()
+	return a.writeEnvConfig(&config)
+}
+
+// writeEnvConfig validates config and replaces env.json atomically
+// Callers hold envWriteMu so concurrent updates are applied one after the other
+func (a *App) writeEnvConfig(config *EnvConfig) error {
 	// Validate required fields
 	if config.Version == "" {
 		return fmt.Errorf("version field is required")
 	}
+	if config.Version != currentEnvVersion {
+		return fmt.Errorf("unsupported env.json version %s, expected %s", config.Version, currentEnvVersion)
+	}
 	if config.ActiveEnvironment == "" {
 		return fmt.Errorf("activeEnvironment field is required")
 	}
@@ -148,6 +198,25 @@ func (a *App) SaveEnvVariables(content string) error {
 		return fmt.Errorf("environments field is required")
 	}
 
+	// Masked secrets stay in the vault
+	if err := stripSecrets(config); err != nil {
+		return err
+	}
+
+	// References are checked as they will be resolved, over the project environments
+	merged := config
+	if projectPath := a.projectEnvFile(); projectPath != "" {
+		if project, err := readEnvConfigFile(projectPath); err == nil {
+			merged = mergeEnvConfigs(project, config)
+		}
+	}
+	if err := checkVariableCycles(merged); err != nil {
+		return err
+	}
+	if err := checkVariableTypes(merged); err != nil {
+		return err
+	}
+
 	envFilePath, err := getEnvFilePath()
 	if err != nil {
 		return err
@@ -159,7 +228,8 @@ func (a *App) SaveEnvVariables(content string) error {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(envFilePath, data, 0644); err != nil {
+	a.rememberEnvFile(envFilePath, data)
+	if _, err := writeFilesAtomically(map[string]string{envFilePath: string(data)}); err != nil {
 		return fmt.Errorf("failed to write env.json: %w", err)
 	}
 
@@ -171,22 +241,45 @@ func (a *App) SaveEnvVariables(content string) error {
 
 // GetFlattenedVariables returns a flattened map of variables for the given environment
 // Priority: environment variables override global variables
+// References to other variables and OS environment variables are resolved after the merge
 func (a *App) GetFlattenedVariables(environment string) (map[string]string, error) {
+	raw, err := a.rawVariables(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, _, err := resolveVariables(raw)
+	return resolved, err
+}
+
+// rawVariables merges the global and environment variables without resolving references
+func (a *App) rawVariables(environment string) (map[string]string, error) {
 	// Load config from cache
 	config, err := a.loadEnvConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	return flattenEnvironment(config, environment)
+}
+
+// flattenEnvironment merges the global variables, then the environments
+// extended by environment from the furthest ancestor, then environment itself
+func flattenEnvironment(config *EnvConfig, environment string) (map[string]string, error) {
+	chain, err := environmentChain(config, environment)
+	if err != nil {
+		return nil, err
+	}
+
 	// Start with global variables
 	result := make(map[string]string)
 	for k, v := range config.Global {
 		result[k] = v
 	}
 
-	// Override with environment-specific variables
-	if envVars, ok := config.Environments[environment]; ok {
-		for k, v := range envVars {
+	// Override with environment-specific variables, closer environments last
+	for _, env := range chain {
+		for k, v := range config.Environments[env] {
 			result[k] = v
 		}
 	}
@@ -194,6 +287,26 @@ func (a *App) GetFlattenedVariables(environment string) (map[string]string, erro
 	return result, nil
 }
 
+// environmentChain returns environment preceded by the environments it
+// extends, the furthest ancestor first. An environment extending an unknown
+// environment or itself, directly or not, is an error.
+func environmentChain(config *EnvConfig, environment string) ([]string, error) {
+	chain := []string{environment}
+	for name := config.Extends[environment]; name != ""; name = config.Extends[name] {
+		if slices.Contains(chain, name) {
+			cycle := append(slices.Clone(chain[slices.Index(chain, name):]), name)
+			return nil, fmt.Errorf("environment cycle: %s", strings.Join(cycle, " -> "))
+		}
+		if _, ok := config.Environments[name]; !ok {
+			return nil, fmt.Errorf("environment %s extends unknown environment %s", chain[len(chain)-1], name)
+		}
+		chain = append(chain, name)
+	}
+
+	slices.Reverse(chain)
+	return chain, nil
+}
+
 // GetActiveEnvironment returns the currently active environment
 func (a *App) GetActiveEnvironment() (string, error) {
 	// Load config from cache
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEnvironmentChain(t *testing.T) {
+	config := &EnvConfig{
+		Environments: map[string]map[string]string{"base": {}, "prod": {}, "prod-eu": {}, "loop-a": {}, "loop-b": {}, "self": {}, "orphan": {}},
+		Extends: map[string]string{
+			"prod":    "base",
+			"prod-eu": "prod",
+			"loop-a":  "loop-b",
+			"loop-b":  "loop-a",
+			"self":    "self",
+			"orphan":  "missing",
+		},
+	}
+
+	tests := []struct {
+		environment string
+		want        []string
+		err         string
+	}{
+		{environment: "base", want: []string{"base"}},
+		{environment: "prod-eu", want: []string{"base", "prod", "prod-eu"}},
+		{environment: "loop-a", err: "environment cycle: loop-a -> loop-b -> loop-a"},
+		{environment: "self", err: "environment cycle: self -> self"},
+		{environment: "orphan", err: "environment orphan extends unknown environment missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.environment, func(t *testing.T) {
+			got, err := environmentChain(config, tt.environment)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("environmentChain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
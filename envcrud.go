@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// updateEnvConfig applies update to the plaintext variables of the user
+// env.json and writes the result, so concurrent updates do not overwrite
+// each other
+func (a *App) updateEnvConfig(update func(config *EnvConfig) error) error {
+	return a.updateEnvConfigWithSecrets("", nil, update)
+}
+
+// updateEnvConfigWithSecrets is updateEnvConfig that also applies
+// updateSecrets to the vault when environment has secrets. The vault is
+// restored when env.json cannot be written.
+func (a *App) updateEnvConfigWithSecrets(environment string, updateSecrets func(secrets *vaultSecrets), update func(config *EnvConfig) error) error {
+	a.envWriteMu.Lock()
+	defer a.envWriteMu.Unlock()
+
+	masked, err := a.readUserEnvConfig()
+	if err != nil {
+		return err
+	}
+	config := withoutSecrets(masked)
+	if config.Extends == nil {
+		config.Extends = make(map[string]string)
+	}
+	if err := update(config); err != nil {
+		return err
+	}
+
+	undo := func() error { return nil }
+	if updateSecrets != nil {
+		hasSecrets, err := hasEnvironmentSecrets(environment)
+		if err != nil {
+			return err
+		}
+		if hasSecrets {
+			undo, err = a.updateVault(updateSecrets)
+			if err != nil {
+				return fmt.Errorf("environment %s has secrets: %w", environment, err)
+			}
+		}
+	}
+
+	if err := a.writeEnvConfig(config); err != nil {
+		if undoErr := undo(); undoErr != nil {
+			return fmt.Errorf("%w (restoring secrets also failed: %v)", err, undoErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// validateName checks the name of an environment or variable
+func validateName(kind string, name string) error {
+	if name == "" {
+		return fmt.Errorf("%s name cannot be empty", kind)
+	}
+	if sanitizeVariableName(name) != name {
+		return fmt.Errorf("invalid %s name %q", kind, name)
+	}
+	return nil
+}
+
+// isSecret reports whether a variable of a scope is stored in the vault, "" meaning global
+func isSecret(environment string, name string) (bool, error) {
+	vault, err := readVaultFile()
+	if err != nil || vault == nil {
+		return false, err
+	}
+	names := vault.Names.Global
+	if environment != "" {
+		names = vault.Names.Environments[environment]
+	}
+	return slices.Contains(names, name), nil
+}
+
+// mergedForUpdate returns config, the user env.json being updated, layered
+// over the project file of the workspace
+func (a *App) mergedForUpdate(config *EnvConfig) (*EnvConfig, error) {
+	projectPath := a.projectEnvFile()
+	if projectPath == "" {
+		return config, nil
+	}
+	project, err := readEnvConfigFile(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	return mergeEnvConfigs(project, config), nil
+}
+
+// environmentExists reports whether an environment is defined by config, the
+// user env.json being updated, or by the project file. Updates check it with
+// envWriteMu held, so the answer is still true when they write.
+func (a *App) environmentExists(config *EnvConfig, name string) (bool, error) {
+	merged, err := a.mergedForUpdate(config)
+	if err != nil {
+		return false, err
+	}
+	_, ok := merged.Environments[name]
+	return ok, nil
+}
+
+// CreateEnvironment adds an empty environment, inheriting from extends when it is not empty
+func (a *App) CreateEnvironment(name string, extends string) error {
+	if err := validateName("environment", name); err != nil {
+		return err
+	}
+
+	return a.updateEnvConfig(func(config *EnvConfig) error {
+		if exists, err := a.environmentExists(config, name); err != nil {
+			return err
+		} else if exists {
+			return fmt.Errorf("environment %s already exists", name)
+		}
+		if extends != "" {
+			if exists, err := a.environmentExists(config, extends); err != nil {
+				return err
+			} else if !exists {
+				return fmt.Errorf("environment %s does not exist", extends)
+			}
+		}
+
+		config.Environments[name] = make(map[string]string)
+		if extends != "" {
+			config.Extends[name] = extends
+		}
+		return nil
+	})
+}
+
+// CloneEnvironment creates an environment with the variables, secrets,
+// parent and protection of source. Only the variables of the user env.json
+// are copied, the project file keeps its values to itself.
+func (a *App) CloneEnvironment(source string, name string) error {
+	if err := validateName("environment", name); err != nil {
+		return err
+	}
+
+	copySecrets := func(secrets *vaultSecrets) {
+		secrets.Environments[name] = maps.Clone(secrets.Environments[source])
+	}
+	return a.updateEnvConfigWithSecrets(source, copySecrets, func(config *EnvConfig) error {
+		merged, err := a.mergedForUpdate(config)
+		if err != nil {
+			return err
+		}
+		if _, ok := merged.Environments[source]; !ok {
+			return fmt.Errorf("environment %s does not exist", source)
+		}
+		if _, exists := merged.Environments[name]; exists {
+			return fmt.Errorf("environment %s already exists", name)
+		}
+
+		clone := maps.Clone(config.Environments[source])
+		if clone == nil {
+			clone = make(map[string]string)
+		}
+		config.Environments[name] = clone
+		if parent := merged.Extends[source]; parent != "" {
+			config.Extends[name] = parent
+		}
+		// A copy of a protected environment is just as sensitive
+		if protection, ok := merged.Protected[source]; ok {
+			if config.Protected == nil {
+				config.Protected = make(map[string]EnvironmentProtection)
+			}
+			config.Protected[name] = protection
+		}
+		return nil
+	})
+}
+
+// RenameEnvironment renames an environment of the user env.json, along with
+// its secrets and the environments extending it
+func (a *App) RenameEnvironment(oldName string, newName string) error {
+	if err := validateName("environment", newName); err != nil {
+		return err
+	}
+
+	moveSecrets := func(secrets *vaultSecrets) {
+		secrets.Environments[newName] = secrets.Environments[oldName]
+		delete(secrets.Environments, oldName)
+	}
+	return a.updateEnvConfigWithSecrets(oldName, moveSecrets, func(config *EnvConfig) error {
+		vars, ok := config.Environments[oldName]
+		if !ok {
+			return fmt.Errorf("environment %s is not defined in your env.json", oldName)
+		}
+		if exists, err := a.environmentExists(config, newName); err != nil {
+			return err
+		} else if exists {
+			return fmt.Errorf("environment %s already exists", newName)
+		}
+		delete(config.Environments, oldName)
+		config.Environments[newName] = vars
+
+		if parent, ok := config.Extends[oldName]; ok {
+			delete(config.Extends, oldName)
+			config.Extends[newName] = parent
+		}
+		for env, parent := range config.Extends {
+			if parent == oldName {
+				config.Extends[env] = newName
+			}
+		}
+		if config.ActiveEnvironment == oldName {
+			config.ActiveEnvironment = newName
+		}
+		if protection, ok := config.Protected[oldName]; ok {
+			delete(config.Protected, oldName)
+			config.Protected[newName] = protection
+		}
+		return nil
+	})
+}
+
+// DeleteEnvironment removes an environment of the user env.json and its
+// secrets. The active environment and environments extended by others
+// cannot be deleted.
+func (a *App) DeleteEnvironment(name string) error {
+	deleteSecrets := func(secrets *vaultSecrets) {
+		delete(secrets.Environments, name)
+	}
+	return a.updateEnvConfigWithSecrets(name, deleteSecrets, func(config *EnvConfig) error {
+		if _, ok := config.Environments[name]; !ok {
+			return fmt.Errorf("environment %s is not defined in your env.json", name)
+		}
+		merged, err := a.mergedForUpdate(config)
+		if err != nil {
+			return err
+		}
+		if merged.ActiveEnvironment == name {
+			return fmt.Errorf("cannot delete the active environment %s", name)
+		}
+		for _, env := range slices.Sorted(maps.Keys(merged.Extends)) {
+			if merged.Extends[env] == name {
+				return fmt.Errorf("cannot delete %s, environment %s extends it", name, env)
+			}
+		}
+
+		delete(config.Environments, name)
+		delete(config.Extends, name)
+		delete(config.Protected, name)
+		return nil
+	})
+}
+
+// SetVariable sets a variable of an environment, "" meaning a global
+// variable. Secrets are updated in the vault.
+func (a *App) SetVariable(environment string, name string, value string) error {
+	if err := validateName("variable", name); err != nil {
+		return err
+	}
+
+	if secret, err := isSecret(environment, name); err != nil {
+		return err
+	} else if secret {
+		return a.SetSecret(environment, name, value)
+	}
+
+	return a.updateEnvConfig(func(config *EnvConfig) error {
+		if environment != "" {
+			if exists, err := a.environmentExists(config, environment); err != nil {
+				return err
+			} else if !exists {
+				return fmt.Errorf("environment %s does not exist", environment)
+			}
+		}
+
+		vars := configScope(config, environment)
+		if vars == nil {
+			// The environment is only defined by the project file
+			vars = make(map[string]string)
+			config.Environments[environment] = vars
+		}
+		vars[name] = value
+		return nil
+	})
+}
+
+// DeleteVariable removes a variable of an environment, "" meaning a global
+// variable. Secrets are removed from the vault.
+func (a *App) DeleteVariable(environment string, name string) error {
+	if secret, err := isSecret(environment, name); err != nil {
+		return err
+	} else if secret {
+		return a.DeleteSecret(environment, name)
+	}
+
+	return a.updateEnvConfig(func(config *EnvConfig) error {
+		vars := configScope(config, environment)
+		if _, ok := vars[name]; !ok {
+			return fmt.Errorf("variable %s is not defined in your env.json", name)
+		}
+		delete(vars, name)
+		return nil
+	})
+}
+
+// SetActiveEnvironment switches the environment used to run requests
+func (a *App) SetActiveEnvironment(name string) error {
+	return a.updateEnvConfig(func(config *EnvConfig) error {
+		if exists, err := a.environmentExists(config, name); err != nil {
+			return err
+		} else if !exists {
+			return fmt.Errorf("environment %s does not exist", name)
+		}
+		config.ActiveEnvironment = name
+		return nil
+	})
+}
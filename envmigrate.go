@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// currentEnvVersion is the env.json format written by this version of the app
+const currentEnvVersion = "1.0"
+
+// envSchemaFile is written next to the user env.json so editors can validate it
+const envSchemaFile = "env.schema.json"
+
+//go:embed schemas/env.schema.json
+var envSchema []byte
+
+// envMigration upgrades an env.json document from one version to the next
+type envMigration struct {
+	from    string
+	to      string
+	migrate func(doc map[string]any) error
+}
+
+// envMigrations upgrade documents step by step, in order, up to
+// currentEnvVersion. A version, and the step to it, is only added when the
+// format changes in a way older versions cannot read.
+var envMigrations = []envMigration{
+	// Files written before env.json was versioned only lack the version
+	{from: "", to: "1.0", migrate: func(doc map[string]any) error { return nil }},
+}
+
+// migrateEnvDocument upgrades an env.json document to currentEnvVersion. It
+// returns the version the document had, "" when it had none, and whether it
+// was changed. Versions newer than currentEnvVersion are rejected.
+func migrateEnvDocument(data []byte) ([]byte, string, bool, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, "", false, fmt.Errorf("invalid JSON format: %w", err)
+	}
+
+	version := ""
+	if v, ok := doc["version"]; ok {
+		s, isString := v.(string)
+		if !isString {
+			return nil, "", false, fmt.Errorf("version must be a string")
+		}
+		version = s
+	}
+	if version == currentEnvVersion {
+		return data, version, false, nil
+	}
+
+	from := version
+	for _, migration := range envMigrations {
+		if migration.from != version {
+			continue
+		}
+		if err := migration.migrate(doc); err != nil {
+			return nil, from, false, fmt.Errorf("failed to upgrade env.json from version %s to %s: %w", migration.from, migration.to, err)
+		}
+		version = migration.to
+		doc["version"] = version
+	}
+
+	if version != currentEnvVersion {
+		if newer, err := isNewerEnvVersion(version); err == nil && newer {
+			return nil, from, false, fmt.Errorf("env.json version %s is newer than this version of Hurl Studio supports (%s), update the app to use it", version, currentEnvVersion)
+		}
+		return nil, from, false, fmt.Errorf("unknown env.json version %q", version)
+	}
+
+	// Decoding into EnvConfig keeps the field order the app writes
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, from, false, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var config EnvConfig
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		return nil, from, false, fmt.Errorf("invalid JSON format: %w", err)
+	}
+	migrated, err = json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, from, false, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return migrated, from, true, nil
+}
+
+// isNewerEnvVersion reports whether a major.minor version comes after currentEnvVersion
+func isNewerEnvVersion(version string) (bool, error) {
+	parse := func(v string) ([2]int, error) {
+		major, minor, ok := strings.Cut(v, ".")
+		if !ok {
+			return [2]int{}, fmt.Errorf("invalid version %q", v)
+		}
+		majorNumber, err := strconv.Atoi(major)
+		if err != nil {
+			return [2]int{}, fmt.Errorf("invalid version %q", v)
+		}
+		minorNumber, err := strconv.Atoi(minor)
+		if err != nil {
+			return [2]int{}, fmt.Errorf("invalid version %q", v)
+		}
+		return [2]int{majorNumber, minorNumber}, nil
+	}
+
+	v, err := parse(version)
+	if err != nil {
+		return false, err
+	}
+	current, err := parse(currentEnvVersion)
+	if err != nil {
+		return false, err
+	}
+	return v[0] > current[0] || v[0] == current[0] && v[1] > current[1], nil
+}
+
+// migrateEnvFile upgrades the user env.json on disk, keeping the original
+// next to it as env.json.<version>.bak. An existing backup is not replaced,
+// it holds the oldest copy.
+func (a *App) migrateEnvFile(path string, data []byte) ([]byte, error) {
+	migrated, from, changed, err := migrateEnvDocument(data)
+	if err != nil || !changed {
+		return migrated, err
+	}
+
+	if from == "" {
+		from = "unversioned"
+	}
+	backupPath := fmt.Sprintf("%s.%s.bak", path, from)
+	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+		if err := os.WriteFile(backupPath, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to back up env.json before upgrading it: %w", err)
+		}
+	}
+
+	a.rememberEnvFile(path, migrated)
+	if _, err := writeFilesAtomically(map[string]string{path: string(migrated)}); err != nil {
+		return nil, fmt.Errorf("failed to write upgraded env.json: %w", err)
+	}
+	return migrated, nil
+}
+
+// writeEnvSchema keeps the JSON Schema next to the user env.json up to date
+func writeEnvSchema(dir string) error {
+	path := filepath.Join(dir, envSchemaFile)
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, envSchema) {
+		return nil
+	}
+	if err := os.WriteFile(path, envSchema, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", envSchemaFile, err)
+	}
+	return nil
+}
+
+// GetEnvSchema returns the JSON Schema of env.json
+func (a *App) GetEnvSchema() string {
+	return string(envSchema)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateEnvDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		from    string
+		changed bool
+		err     string
+	}{
+		{name: "current version", doc: `{"version": "1.0", "activeEnvironment": "dev", "environments": {}}`, from: "1.0"},
+		{name: "no version", doc: `{"activeEnvironment": "dev", "global": {"a": "1"}, "environments": {"dev": {}}}`, changed: true},
+		{name: "newer version", doc: `{"version": "2.3"}`, err: "env.json version 2.3 is newer than this version of Hurl Studio supports (1.0)"},
+		{name: "unknown version", doc: `{"version": "beta"}`, err: `unknown env.json version "beta"`},
+		{name: "version that is not a string", doc: `{"version": 1}`, err: "version must be a string"},
+		{name: "invalid JSON", doc: `{"version": `, err: "invalid JSON format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, from, changed, err := migrateEnvDocument([]byte(tt.doc))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if from != tt.from || changed != tt.changed {
+				t.Errorf("from = %q, changed = %v, want %q, %v", from, changed, tt.from, tt.changed)
+			}
+
+			var config EnvConfig
+			if err := json.Unmarshal(data, &config); err != nil {
+				t.Fatal(err)
+			}
+			if config.Version != currentEnvVersion || config.ActiveEnvironment != "dev" {
+				t.Errorf("migrated document = %s", data)
+			}
+		})
+	}
+}
+
+func TestMigrateEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "env.json")
+	original := `{"activeEnvironment": "dev", "environments": {"dev": {"a": "1"}}}`
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewApp()
+	migrated, err := a.migrateEnvFile(path, []byte(original))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	onDisk, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(onDisk) != string(migrated) || !strings.Contains(string(onDisk), `"version": "1.0"`) {
+		t.Errorf("env.json = %s, want the upgraded document", onDisk)
+	}
+	backup, err := os.ReadFile(path + ".unversioned.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) != original {
+		t.Errorf("backup = %s, want %s", backup, original)
+	}
+
+	// An upgraded file is left alone
+	if _, err := a.migrateEnvFile(path, onDisk); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := os.ReadFile(path); string(again) != string(onDisk) {
+		t.Errorf("env.json changed again: %s", again)
+	}
+}
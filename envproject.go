@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// projectEnvFiles are the names of a project env.json relative to a
+// workspace directory, in lookup order
+var projectEnvFiles = []string{
+	"env.json",
+	filepath.Join(".hurlstudio", "env.json"),
+}
+
+// envLayer is one env.json file taking part in the merged configuration.
+// Name is user or project.
+type envLayer struct {
+	Name   string
+	Path   string
+	Config *EnvConfig
+}
+
+// LayeredVariable is a variable of the flattened configuration together with
+// the layer and scope its value comes from. Layer is user or project, Scope
+// is global or environment. Environment names the environment of an
+// environment value, an ancestor when the value is inherited. Value is as
+// written and Resolved has its references expanded. The value of a secret is
+// masked.
+type LayeredVariable struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Resolved    string `json:"resolved"`
+	Layer       string `json:"layer"`
+	Scope       string `json:"scope"`
+	Environment string `json:"environment,omitempty"`
+	Path        string `json:"path"`
+	Secret      bool   `json:"secret"`
+	Type        string `json:"type"`
+}
+
+// workspaceRoot returns the repository root of dir, the closest directory
+// holding .git, so browsing the folders of a project keeps one workspace.
+// Outside a repository the workspace is dir itself.
+func workspaceRoot(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	for current := dir; ; {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir
+		}
+		current = parent
+	}
+}
+
+// projectEnvFile returns the project env.json of the current workspace, ""
+// when there is none
+func (a *App) projectEnvFile() string {
+	return findProjectEnvFile(workspaceRoot(a.currentDir))
+}
+
+// findProjectEnvFile returns the project env.json of a workspace root. Only
+// dir itself is searched, a file in a parent belongs to another project. The
+// user's own env.json is never treated as a project file. It returns "" when
+// there is none.
+func findProjectEnvFile(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	userPath, _ := getEnvFilePath()
+
+	for _, name := range projectEnvFiles {
+		path := filepath.Join(dir, name)
+		if path == userPath {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// readEnvConfigFile parses an env.json file other than the user's
+func readEnvConfigFile(path string) (*EnvConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// Project files are shared, they are only upgraded in memory
+	data, _, _, err = migrateEnvDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var config EnvConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if config.Global == nil {
+		config.Global = make(map[string]string)
+	}
+	if config.Environments == nil {
+		config.Environments = make(map[string]map[string]string)
+	}
+	return &config, nil
+}
+
+// mergeEnvConfigs layers the user config over the project config. Global
+// variables, each environment and the variable types are merged key by key,
+// user values winning. Protections of both files apply.
+func mergeEnvConfigs(project *EnvConfig, user *EnvConfig) *EnvConfig {
+	merged := &EnvConfig{
+		Version:           user.Version,
+		ActiveEnvironment: user.ActiveEnvironment,
+		Global:            make(map[string]string),
+		Environments:      make(map[string]map[string]string),
+		Extends:           make(map[string]string),
+		Types:             make(map[string]string),
+		Protected:         make(map[string]EnvironmentProtection),
+	}
+	if merged.ActiveEnvironment == "" {
+		merged.ActiveEnvironment = project.ActiveEnvironment
+	}
+
+	for _, config := range []*EnvConfig{project, user} {
+		for k, v := range config.Global {
+			merged.Global[k] = v
+		}
+		for env, parent := range config.Extends {
+			merged.Extends[env] = parent
+		}
+		for name, variableType := range config.Types {
+			merged.Types[name] = variableType
+		}
+		// Either file can protect an environment, the other cannot lift it
+		for env, protection := range config.Protected {
+			protection.BlockMutations = protection.BlockMutations || merged.Protected[env].BlockMutations
+			merged.Protected[env] = protection
+		}
+		for env, vars := range config.Environments {
+			if merged.Environments[env] == nil {
+				merged.Environments[env] = make(map[string]string)
+			}
+			for k, v := range vars {
+				merged.Environments[env][k] = v
+			}
+		}
+	}
+	return merged
+}
+
+// envLayers returns the env.json files of the current workspace, the user
+// file first as it takes precedence
+func (a *App) envLayers() ([]envLayer, error) {
+	userPath, err := getEnvFilePath()
+	if err != nil {
+		return nil, err
+	}
+	user, err := a.readUserEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+	layers := []envLayer{{Name: "user", Path: userPath, Config: user}}
+
+	if projectPath := a.projectEnvFile(); projectPath != "" {
+		project, err := readEnvConfigFile(projectPath)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, envLayer{Name: "project", Path: projectPath, Config: project})
+	}
+	return layers, nil
+}
+
+// GetProjectEnvFile returns the project env.json of the current workspace,
+// or "" when the workspace has none
+func (a *App) GetProjectEnvFile() string {
+	return a.projectEnvFile()
+}
+
+// GetLayeredVariables returns the flattened variables of an environment with
+// the file each value comes from
+func (a *App) GetLayeredVariables(environment string) ([]LayeredVariable, error) {
+	layers, err := a.envLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := a.loadEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+	chain, err := environmentChain(config, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	// The environment overrides the environments it extends, which override
+	// the global variables. Within each, the user file overrides the project
+	// file, so the first match wins.
+	resolved := make(map[string]LayeredVariable)
+	add := func(scope string, env string) {
+		for _, layer := range layers {
+			vars := layer.Config.Global
+			if scope == "environment" {
+				vars = layer.Config.Environments[env]
+			}
+			for name, value := range vars {
+				if _, ok := resolved[name]; ok {
+					continue
+				}
+				resolved[name] = LayeredVariable{
+					Name:        name,
+					Value:       value,
+					Layer:       layer.Name,
+					Scope:       scope,
+					Environment: env,
+					Path:        layer.Path,
+					Secret:      layer.Name == "user" && value == secretMask,
+				}
+			}
+		}
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		add("environment", chain[i])
+	}
+	add("global", "")
+
+	raw := make(map[string]string, len(resolved))
+	for name, variable := range resolved {
+		raw[name] = variable.Value
+	}
+	values, _, err := resolveVariables(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make([]LayeredVariable, 0, len(resolved))
+	for _, name := range slices.Sorted(maps.Keys(resolved)) {
+		variable := resolved[name]
+		variable.Resolved = values[name]
+		variable.Type = config.Types[name]
+		variables = append(variables, variable)
+	}
+	return variables, nil
+}
+
+// GetEnvironments returns the names of the environments of every layer
+func (a *App) GetEnvironments() ([]string, error) {
+	config, err := a.loadEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(config.Environments)), nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspaceProjectEnvFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	nested := filepath.Join(repo, "api", "users")
+	bare := filepath.Join(root, "bare", "sub")
+	for _, dir := range []string{filepath.Join(repo, ".git"), nested, bare} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{filepath.Join(repo, "env.json"), filepath.Join(root, "bare", "env.json")} {
+		if err := os.WriteFile(path, []byte(`{"environments":{}}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		root    string
+		project string
+	}{
+		{name: "repository root", dir: repo, root: repo, project: filepath.Join(repo, "env.json")},
+		{name: "folder of the repository", dir: nested, root: repo, project: filepath.Join(repo, "env.json")},
+		{name: "outside a repository", dir: bare, root: bare, project: ""},
+		{name: "no directory", dir: "", root: "", project: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := workspaceRoot(tt.dir); got != tt.root {
+				t.Errorf("workspaceRoot(%q) = %q, want %q", tt.dir, got, tt.root)
+			}
+			a := &App{currentDir: tt.dir}
+			if got := a.projectEnvFile(); got != tt.project {
+				t.Errorf("projectEnvFile() = %q, want %q", got, tt.project)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// envChangedEvent is emitted with the path of an env.json changed outside the app
+const envChangedEvent = "env:changed"
+
+// envChangeDelay groups the events of a single save, editors often write a
+// file in several steps
+const envChangeDelay = 100 * time.Millisecond
+
+// startEnvWatcher watches the env.json files the environment config is loaded
+// from. The files to watch are set by loadEnvConfig.
+func (a *App) startEnvWatcher() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		fmt.Printf("Error watching env.json: %v\n", err)
+		return
+	}
+
+	a.envWatchMu.Lock()
+	a.envWatcher = watcher
+	a.envWatchMu.Unlock()
+
+	go func() {
+		timers := make(map[string]*time.Timer)
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				path := filepath.Clean(event.Name)
+				a.envWatchMu.Lock()
+				watched := a.envWatchFiles[path]
+				a.envWatchMu.Unlock()
+				if !watched || event.Has(fsnotify.Chmod) {
+					continue
+				}
+				if timer, ok := timers[path]; ok {
+					timer.Stop()
+				}
+				timers[path] = time.AfterFunc(envChangeDelay, func() { a.envFileChanged(path) })
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				fmt.Printf("Error watching env.json: %v\n", err)
+			}
+		}
+	}()
+}
+
+// watchEnvFiles replaces the watched env.json files. The directories are
+// watched rather than the files, so files replaced by a rename are still
+// followed.
+func (a *App) watchEnvFiles(paths []string) {
+	a.envWatchMu.Lock()
+	defer a.envWatchMu.Unlock()
+	if a.envWatcher == nil {
+		return
+	}
+
+	files := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, path := range paths {
+		files[filepath.Clean(path)] = true
+		dirs[filepath.Dir(filepath.Clean(path))] = true
+	}
+
+	for _, dir := range a.envWatcher.WatchList() {
+		if !dirs[dir] {
+			a.envWatcher.Remove(dir)
+		}
+	}
+	for dir := range dirs {
+		if err := a.envWatcher.Add(dir); err != nil {
+			fmt.Printf("Error watching %s: %v\n", dir, err)
+		}
+	}
+	a.envWatchFiles = files
+}
+
+// rememberEnvFile records content the app wrote to an env.json, so the
+// watcher does not report the app's own changes
+func (a *App) rememberEnvFile(path string, content []byte) {
+	a.envWatchMu.Lock()
+	defer a.envWatchMu.Unlock()
+	if a.envFileHashes == nil {
+		a.envFileHashes = make(map[string][sha256.Size]byte)
+	}
+	a.envFileHashes[filepath.Clean(path)] = sha256.Sum256(content)
+}
+
+// isWatchedEnvFile reports whether path is one of the watched env.json files
+func (a *App) isWatchedEnvFile(path string) bool {
+	a.envWatchMu.Lock()
+	defer a.envWatchMu.Unlock()
+	return a.envWatchFiles[filepath.Clean(path)]
+}
+
+// envFileChanged reloads the environment config when an env.json was changed
+// outside the app and tells the frontend
+func (a *App) envFileChanged(path string) {
+	// A deleted file has no content, it is recreated when loaded
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Error reading %s: %v\n", path, err)
+		return
+	}
+
+	hash := sha256.Sum256(content)
+	a.envWatchMu.Lock()
+	if a.envFileHashes == nil {
+		a.envFileHashes = make(map[string][sha256.Size]byte)
+	}
+	unchanged := a.envFileHashes[path] == hash
+	a.envFileHashes[path] = hash
+	a.envWatchMu.Unlock()
+	if unchanged {
+		return
+	}
+
+	a.invalidateEnvCache()
+	if _, err := a.loadEnvConfig(); err != nil {
+		// The frontend shows the error when it reloads
+		fmt.Printf("Error reloading env.json: %v\n", err)
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, envChangedEvent, path)
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // SupportedExtensions defines the file extensions that can be displayed and created
-var SupportedExtensions = []string{".hurl", ".md", ".markdown"}
+var SupportedExtensions = []string{".hurl", ".http", ".md", ".markdown"}
 
 // isSupportedExtension checks if the given extension is supported
 func isSupportedExtension(ext string) bool {
@@ -95,7 +97,7 @@ func (a *App) ListFiles(path string) ([]FileEntry, error) {
 			continue
 		}
 
-		// Only include directories, .hurl files, or markdown files
+		// Only include directories and files with a supported extension
 		if !entry.IsDir() {
 			ext := strings.ToLower(filepath.Ext(entry.Name()))
 			if !isSupportedExtension(ext) {
@@ -233,6 +235,10 @@ func (a *App) CreateFile(name string) error {
 GET https://example.com
 
 HTTP 200
+`
+	case ".http":
+		initialContent = `### New HTTP Request
+GET https://example.com
 `
 	case ".md", ".markdown":
 		initialContent = `# New Markdown File
@@ -444,3 +450,37 @@ func (a *App) LoadLastOpenedState() (CurrentFilesState, error) {
 
 	return state, nil
 }
+
+// SelectFile shows a native open file dialog and returns the chosen path
+// pattern is a filter such as "*.json", an empty string shows all files
+func (a *App) SelectFile(title string, pattern string) (string, error) {
+	options := runtime.OpenDialogOptions{
+		Title:            title,
+		DefaultDirectory: a.currentDir,
+	}
+	if pattern != "" {
+		options.Filters = []runtime.FileFilter{
+			{DisplayName: pattern, Pattern: pattern},
+		}
+	}
+
+	path, err := runtime.OpenFileDialog(a.ctx, options)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file dialog: %w", err)
+	}
+
+	return path, nil
+}
+
+// SelectDirectory shows a native directory picker and returns the chosen path
+func (a *App) SelectDirectory(title string) (string, error) {
+	path, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:            title,
+		DefaultDirectory: a.currentDir,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to open directory dialog: %w", err)
+	}
+
+	return path, nil
+}
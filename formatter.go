@@ -0,0 +1,460 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FormatChange describes the formatting of one file. Diff is a unified diff of
+// the change, empty when the file is already formatted.
+type FormatChange struct {
+	Path  string `json:"path"`
+	Diff  string `json:"diff"`
+	Error string `json:"error,omitempty"`
+}
+
+// hurlSectionOrder is the order sections are written in by the formatter
+var hurlSectionOrder = []string{
+	"QueryStringParams", "Query",
+	"FormParams", "Form",
+	"MultipartFormData", "Multipart",
+	"Cookies",
+	"BasicAuth",
+	"Options",
+	"Captures",
+	"Asserts",
+}
+
+// FormatHurl formats hurl content, such as the editor buffer saved with Cmd+S
+func (a *App) FormatHurl(content string) (string, error) {
+	return formatHurl(content)
+}
+
+// PreviewFormatHurl formats a .hurl file, or every .hurl file below a folder,
+// and returns the diff of each file that would change without writing anything
+func (a *App) PreviewFormatHurl(path string) ([]FormatChange, error) {
+	files, err := collectHurlFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []FormatChange{}
+	for _, file := range files {
+		content, err := a.GetFileContent(file)
+		if err != nil {
+			return nil, err
+		}
+
+		formatted, err := formatHurl(content)
+		if err != nil {
+			changes = append(changes, FormatChange{Path: file, Error: err.Error()})
+			continue
+		}
+		if formatted != content {
+			changes = append(changes, FormatChange{Path: file, Diff: unifiedDiff(file, content, formatted)})
+		}
+	}
+
+	return changes, nil
+}
+
+// ApplyFormatHurl formats and saves the given .hurl files
+func (a *App) ApplyFormatHurl(paths []string) error {
+	for _, path := range paths {
+		content, err := a.GetFileContent(path)
+		if err != nil {
+			return err
+		}
+
+		formatted, err := formatHurl(content)
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", path, err)
+		}
+		if formatted == content {
+			continue
+		}
+		if err := a.SaveFile(path, formatted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectHurlFiles returns path when it is a file, or every .hurl file below it when it is a folder
+func collectHurlFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info for %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != path && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && strings.ToLower(filepath.Ext(p)) == ".hurl" {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory %s: %w", path, err)
+	}
+	return files, nil
+}
+
+// hurlFormatter rewrites a parsed hurl file, keeping comments next to the
+// line they precede
+type hurlFormatter struct {
+	lines []string
+	// prev is the 1-based last line already written or skipped
+	prev int
+	out  []string
+}
+
+// formatHurl normalises indentation, spacing and section order, and pretty
+// prints JSON bodies. Files with syntax errors are not formatted.
+func formatHurl(content string) (string, error) {
+	file := parseHurl(content)
+	if len(file.Errors) > 0 {
+		e := file.Errors[0]
+		return "", fmt.Errorf("line %d: %s", e.Span.Start.Line, e.Message)
+	}
+
+	f := &hurlFormatter{lines: strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")}
+	for i, entry := range file.Entries {
+		if i > 0 {
+			f.out = append(f.out, "")
+		}
+		f.formatEntry(entry)
+	}
+
+	// Comments after the last entry are kept at the end of the file
+	trailing := f.commentsBefore(len(f.lines) + 1)
+	if len(trailing) > 0 && len(f.out) > 0 {
+		f.out = append(f.out, "")
+	}
+	f.out = append(f.out, trailing...)
+
+	if len(f.out) == 0 {
+		return "", nil
+	}
+	return strings.Join(f.out, "\n") + "\n", nil
+}
+
+// commentsBefore returns the comment lines between the last written line and
+// the 1-based line, and marks them as written
+func (f *hurlFormatter) commentsBefore(line int) []string {
+	var comments []string
+	for i := f.prev + 1; i < line && i <= len(f.lines); i++ {
+		trimmed := strings.TrimSpace(f.lines[i-1])
+		if strings.HasPrefix(trimmed, "#") {
+			comments = append(comments, trimmed)
+		}
+	}
+	if line-1 > f.prev {
+		f.prev = line - 1
+	}
+	return comments
+}
+
+// node returns the comments preceding span followed by the formatted line
+func (f *hurlFormatter) node(span Span, text string) []string {
+	lines := f.commentsBefore(span.Start.Line)
+	f.prev = span.End.Line
+	return append(lines, text)
+}
+
+func (f *hurlFormatter) formatEntry(entry HurlEntry) {
+	req := entry.Request
+	f.out = append(f.out, f.node(Span{Start: req.Span.Start, End: req.Span.Start},
+		withComment(req.Method+" "+req.URL, f.lines[req.Span.Start.Line-1]))...)
+	f.formatMessage(req.Headers, req.Sections, req.Body)
+
+	if resp := entry.Response; resp != nil {
+		f.out = append(f.out, f.node(Span{Start: resp.Span.Start, End: resp.Span.Start},
+			withComment(resp.Version+" "+resp.Status, f.lines[resp.Span.Start.Line-1]))...)
+		f.formatMessage(resp.Headers, resp.Sections, resp.Body)
+	}
+}
+
+// formatMessage writes headers, sections in their canonical order and the body
+func (f *hurlFormatter) formatMessage(headers []HurlKeyValue, sections []HurlSection, body *HurlBody) {
+	for _, h := range headers {
+		f.out = append(f.out, f.node(h.Span, formatKeyValueLine(f.lines[h.Span.Start.Line-1]))...)
+	}
+
+	// Sections are formatted in source order so comments are picked up, then reordered
+	formatted := make(map[string][]string)
+	for _, section := range sections {
+		block := f.node(Span{Start: section.Span.Start, End: section.Span.Start},
+			withComment("["+section.Name+"]", f.lines[section.Span.Start.Line-1]))
+		for _, item := range section.Items {
+			block = append(block, f.node(item.Span, formatKeyValueLine(f.lines[item.Span.Start.Line-1]))...)
+		}
+		for _, c := range section.Captures {
+			block = append(block, f.node(c.Span, formatCaptureLine(f.lines[c.Span.Start.Line-1]))...)
+		}
+		for _, assert := range section.Asserts {
+			block = append(block, f.node(assert.Span, formatAssertLine(f.lines[assert.Span.Start.Line-1], assert))...)
+		}
+		formatted[section.Name] = append(formatted[section.Name], block...)
+	}
+	for _, name := range hurlSectionOrder {
+		f.out = append(f.out, formatted[name]...)
+	}
+
+	if body != nil {
+		f.out = append(f.out, f.commentsBefore(body.Span.Start.Line)...)
+		f.out = append(f.out, f.formatBody(body)...)
+		f.prev = body.Span.End.Line
+	}
+}
+
+// formatBody pretty prints JSON bodies and keeps every other body as written
+func (f *hurlFormatter) formatBody(body *HurlBody) []string {
+	if body.Kind == "json" {
+		if pretty, ok := prettyJSONBody(body.Text); ok {
+			return strings.Split(pretty, "\n")
+		}
+	}
+
+	lines := append([]string{}, f.lines[body.Span.Start.Line-1:body.Span.End.Line]...)
+	lines[0] = strings.TrimLeft(lines[0], " \t")
+	if body.Kind == "multiline" {
+		lines[len(lines)-1] = strings.TrimSpace(lines[len(lines)-1])
+	}
+	return lines
+}
+
+// withComment appends the trailing comment of the raw source line to text
+func withComment(text string, raw string) string {
+	content := stripHurlComment(raw)
+	if comment := strings.TrimSpace(raw[len(content):]); comment != "" {
+		return text + " " + comment
+	}
+	return text
+}
+
+// splitRawKeyValue splits a raw "key: value" line without decoding escapes
+func splitRawKeyValue(content string) (string, string) {
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case ':':
+			return strings.TrimSpace(content[:i]), strings.TrimSpace(content[i+1:])
+		}
+	}
+	return strings.TrimSpace(content), ""
+}
+
+// formatKeyValueLine writes a header or section line as "key: value"
+func formatKeyValueLine(raw string) string {
+	key, value := splitRawKeyValue(stripHurlComment(raw))
+	text := key + ":"
+	if value != "" {
+		text += " " + value
+	}
+	return withComment(text, raw)
+}
+
+// formatCaptureLine writes a capture with single spaces between its tokens
+func formatCaptureLine(raw string) string {
+	key, value := splitRawKeyValue(stripHurlComment(raw))
+	tokens, _ := tokenizeHurl(value)
+	return withComment(key+": "+strings.Join(tokens, " "), raw)
+}
+
+// formatAssertLine writes an assert with single spaces between its tokens,
+// keeping the predicate value as written
+func formatAssertLine(raw string, assert HurlAssert) string {
+	content := strings.TrimSpace(stripHurlComment(raw))
+	tokens, offsets := tokenizeHurl(content)
+
+	// Count the tokens before the predicate value
+	n := 2
+	if hurlQueryArgs[assert.Query.Type] {
+		n++
+	}
+	for _, filter := range assert.Filters {
+		n += 1 + len(filter.Args)
+	}
+	if assert.Not {
+		n++
+	}
+	if n > len(tokens) {
+		n = len(tokens)
+	}
+
+	text := strings.Join(tokens[:n], " ")
+	if n < len(tokens) {
+		text += " " + strings.TrimSpace(content[offsets[n]:])
+	}
+	return withComment(text, raw)
+}
+
+// prettyJSONBody indents a JSON body by two spaces. Templates such as
+// {{id}} are allowed in value position.
+func prettyJSONBody(text string) (string, bool) {
+	if !json.Valid([]byte(templatePlaceholders(text))) {
+		return "", false
+	}
+
+	var sb strings.Builder
+	indent := 0
+	newline := func() {
+		sb.WriteString("\n" + strings.Repeat("  ", indent))
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '"':
+			end := i + 1
+			for end < len(text) && text[end] != '"' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			sb.WriteString(text[i : end+1])
+			i = end
+		case strings.HasPrefix(text[i:], "{{"):
+			end := strings.Index(text[i:], "}}")
+			sb.WriteString(text[i : i+end+2])
+			i += end + 1
+		case c == '{' || c == '[':
+			// Empty objects and arrays stay on one line
+			next := strings.TrimLeft(text[i+1:], " \t\r\n")
+			if next != "" && (c == '{' && next[0] == '}' || c == '[' && next[0] == ']') {
+				sb.WriteByte(c)
+				sb.WriteByte(next[0])
+				i = len(text) - len(next)
+				continue
+			}
+			sb.WriteByte(c)
+			indent++
+			newline()
+		case c == '}' || c == ']':
+			indent--
+			newline()
+			sb.WriteByte(c)
+		case c == ',':
+			sb.WriteByte(c)
+			newline()
+		case c == ':':
+			sb.WriteString(": ")
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String(), true
+}
+
+// unifiedDiff returns a unified diff between two versions of a file
+func unifiedDiff(path string, before string, after string) string {
+	a := strings.Split(strings.TrimSuffix(before, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(after, "\n"), "\n")
+
+	// Longest common subsequence table, lcs[i][j] is the length for a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte
+		text string
+		// aLine and bLine are the 0-based lines in each version before this line
+		aLine, bLine int
+	}
+	var ops []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffLine{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffLine{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffLine{'+', b[j], i, j})
+			j++
+		}
+	}
+
+	const context = 3
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", path, path)
+
+	for k := 0; k < len(ops); {
+		if ops[k].op == ' ' {
+			k++
+			continue
+		}
+
+		// Grow the hunk while changes are close enough to share context
+		start := max(0, k-context)
+		end := k
+		for end < len(ops) {
+			if ops[end].op != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].op == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end = min(len(ops), end+context)
+				break
+			}
+			end = run
+		}
+
+		aCount, bCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.op != '+' {
+				aCount++
+			}
+			if op.op != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", ops[start].aLine+1, aCount, ops[start].bLine+1, bCount)
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.op)
+			sb.WriteString(op.text + "\n")
+		}
+		k = end
+	}
+
+	return sb.String()
+}
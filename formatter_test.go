@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatHurl(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		err     string
+	}{
+		{
+			name:    "spacing and section order",
+			content: "GET   https://a\nAccept:application/json\nHTTP 200\n[Asserts]\njsonpath \"$.id\"   ==   1\n[Captures]\nid: jsonpath \"$.id\"\n",
+			want:    "GET https://a\nAccept: application/json\nHTTP 200\n[Captures]\nid: jsonpath \"$.id\"\n[Asserts]\njsonpath \"$.id\" == 1\n",
+		},
+		{
+			name:    "JSON body, blank lines and comments",
+			content: "# c\nPOST https://b\n{\"a\":1,\"b\":[1,2]}\n\n\n\nGET https://c\n[QueryStringParams]\nq:  x\n# end\n",
+			want:    "# c\nPOST https://b\n{\n  \"a\": 1,\n  \"b\": [\n    1,\n    2\n  ]\n}\n\nGET https://c\n[QueryStringParams]\nq: x\n\n# end\n",
+		},
+		{
+			name:    "multiline body kept as written",
+			content: "GET https://a\n```\nraw  text\n```\nHTTP/2 *\n",
+			want:    "GET https://a\n```\nraw  text\n```\nHTTP/2 *\n",
+		},
+		{
+			name:    "CRLF line endings",
+			content: "GET https://a\r\nHTTP 200\r\n",
+			want:    "GET https://a\nHTTP 200\n",
+		},
+		{
+			name:    "empty file",
+			content: "",
+			want:    "",
+		},
+		{
+			name:    "syntax error",
+			content: "GET https://a\nnot valid\n",
+			err:     `line 2: expected "key: value", found "not valid"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatHurl(tt.content)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("formatHurl() = %q, want %q", got, tt.want)
+			}
+
+			// Formatting a formatted file changes nothing
+			again, err := formatHurl(got)
+			if err != nil {
+				t.Fatalf("formatted file does not parse: %v", err)
+			}
+			if again != got {
+				t.Errorf("formatHurl() is not idempotent: %q, then %q", got, again)
+			}
+		})
+	}
+}
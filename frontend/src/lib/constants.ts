@@ -8,7 +8,8 @@
 export const SUPPORTED_EXTENSIONS = {
 	MARKDOWN: ['.md', '.markdown'],
 	HURL: ['.hurl'],
-	ALL: ['.md', '.markdown', '.hurl']
+	HTTP: ['.http'],
+	ALL: ['.md', '.markdown', '.hurl', '.http']
 } as const;
 
 /**
@@ -73,7 +74,7 @@ export const SIDEBAR_CONFIG = {
 } as const;
 
 export const VALIDATION_MESSAGES = {
-	INVALID_EXTENSION: 'Invalid file extension. File must end with .md, .markdown, or .hurl',
+	INVALID_EXTENSION: 'Invalid file extension. File must end with .md, .markdown, .hurl, or .http',
 	EMPTY_FILENAME: 'Filename cannot be empty',
 	FILE_EXISTS: 'A file with this name already exists',
 	INVALID_CHARACTERS: 'Filename contains invalid characters'
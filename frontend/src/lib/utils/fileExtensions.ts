@@ -2,7 +2,7 @@
  * Utility functions for file extension handling
  */
 
-export type FileExtension = 'md' | 'markdown' | 'hurl' | 'unknown';
+export type FileExtension = 'md' | 'markdown' | 'hurl' | 'http' | 'unknown';
 
 export function getFileExtension(filename: string): string {
 	return filename.split('.').pop()?.toLowerCase() || '';
@@ -17,11 +17,16 @@ export function isHurlFile(filename: string): boolean {
 	return getFileExtension(filename) === 'hurl';
 }
 
+export function isHttpFile(filename: string): boolean {
+	return getFileExtension(filename) === 'http';
+}
+
 export function getEditorLanguage(filename: string): string {
 	const ext = getFileExtension(filename);
 
 	if (ext === 'md' || ext === 'markdown') return 'markdown';
 	if (ext === 'hurl') return 'plaintext'; // Can add custom Hurl syntax later
+	if (ext === 'http') return 'plaintext';
 	if (ext === 'json') return 'json';
 	if (ext === 'html') return 'html';
 	if (ext === 'css') return 'css';
@@ -34,10 +39,10 @@ export function getEditorLanguage(filename: string): string {
 }
 
 export function hasValidExtension(filename: string): boolean {
-	const validExtensions = ['.md', '.markdown', '.hurl'];
+	const validExtensions = ['.md', '.markdown', '.hurl', '.http'];
 	return validExtensions.some((ext) => filename.toLowerCase().endsWith(ext));
 }
 
 export function getExtensionValidationError(): string {
-	return 'Invalid file extension. File must end with .md, .markdown, or .hurl';
+	return 'Invalid file extension. File must end with .md, .markdown, .hurl, or .http';
 }
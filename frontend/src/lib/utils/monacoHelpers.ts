@@ -3,30 +3,15 @@
  */
 
 import * as monaco from 'monaco-editor';
-import { HTTP_METHODS } from '$lib/constants';
+import { ParseHurlContent } from '$lib/wailsjs/go/main/App';
 
-export function findHurlEntries(text: string): number[] {
-	const lines = text.split('\n');
-	const entryLines: number[] = [];
-
-	lines.forEach((line, index) => {
-		const trimmed = line.trim();
-
-		// Skip empty lines and comments
-		if (!trimmed || trimmed.startsWith('#')) {
-			return;
-		}
-
-		// Check if line starts with an HTTP method followed by space
-		for (const method of HTTP_METHODS) {
-			if (trimmed.startsWith(method + ' ') || trimmed === method) {
-				entryLines.push(index + 1); // Monaco uses 1-based line numbers
-				break;
-			}
-		}
-	});
-
-	return entryLines;
+/**
+ * Find the start line of each Hurl entry using the backend parser, so entry
+ * numbers always match the ones hurl uses for --from-entry/--to-entry
+ */
+export async function findHurlEntries(text: string): Promise<number[]> {
+	const file = await ParseHurlContent(text);
+	return file.entries.map((entry) => entry.request.span.start.line);
 }
 
 export class DisposableManager {
@@ -69,9 +54,9 @@ export function createHurlCodeLensProvider(
 	let commandIdCounter = 0;
 
 	return {
-		provideCodeLenses: (model) => {
+		provideCodeLenses: async (model) => {
 			const text = model.getValue();
-			const entryLines = findHurlEntries(text);
+			const entryLines = await findHurlEntries(text);
 
 			// Clear previous command disposables
 			disposableManager.clear();
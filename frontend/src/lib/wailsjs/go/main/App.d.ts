@@ -2,42 +2,132 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function ApplyFormatHurl(arg1:Array<string>):Promise<void>;
+
+export function ApplyRenameVariable(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function ClearCurrentFile():Promise<main.CurrentFilesState>;
 
+export function CloneEnvironment(arg1:string,arg2:string):Promise<void>;
+
+export function ConvertHttpToHurl(arg1:string,arg2:string):Promise<main.ConversionResult>;
+
+export function ConvertHurlToHttp(arg1:string):Promise<main.ConversionResult>;
+
 export function CreateDir(arg1:string):Promise<void>;
 
+export function CreateEnvironment(arg1:string,arg2:string):Promise<void>;
+
 export function CreateFile(arg1:string):Promise<void>;
 
+export function DeleteEnvironment(arg1:string):Promise<void>;
+
 export function DeleteFile(arg1:string):Promise<void>;
 
+export function DeleteSecret(arg1:string,arg2:string):Promise<void>;
+
+export function DeleteVariable(arg1:string,arg2:string):Promise<void>;
+
+export function DisableEntry(arg1:string,arg2:number):Promise<void>;
+
+export function DuplicateEntry(arg1:string,arg2:number):Promise<void>;
+
+export function EnableEntry(arg1:string,arg2:number):Promise<void>;
+
+export function ExportEnvironment(arg1:string,arg2:string):Promise<string>;
+
+export function ExportPostmanCollection(arg1:string,arg2:string,arg3:string):Promise<main.ExportResult>;
+
+export function ExtractEntry(arg1:string,arg2:number,arg3:string):Promise<void>;
+
+export function FindDefinition(arg1:string,arg2:number,arg3:number):Promise<main.SymbolLocation>;
+
+export function FindReferences(arg1:string,arg2:string,arg3:number,arg4:number):Promise<Array<main.SymbolLocation>>;
+
+export function FindUnusedVariables(arg1:string,arg2:string):Promise<Array<string>>;
+
+export function FormatHurl(arg1:string):Promise<string>;
+
+export function GenerateFromOpenAPI(arg1:string,arg2:string):Promise<main.ImportResult>;
+
 export function GetActiveEnvironment():Promise<string>;
 
+export function GetAuditLog(arg1:number):Promise<Array<main.AuditEntry>>;
+
+export function GetCompletions(arg1:string,arg2:number,arg3:number):Promise<Array<main.CompletionItem>>;
+
+export function GetCompletionsForContent(arg1:string,arg2:string,arg3:number,arg4:number):Promise<Array<main.CompletionItem>>;
+
 export function GetCurrentFilesState():Promise<main.CurrentFilesState>;
 
+export function GetEnvSchema():Promise<string>;
+
+export function GetEnvironmentProtection(arg1:string):Promise<main.EnvironmentProtection>;
+
+export function GetEnvironments():Promise<Array<string>>;
+
 export function GetExistingReport(arg1:string):Promise<string>;
 
 export function GetFileContent(arg1:string):Promise<string>;
 
+export function GetFileOutline(arg1:string):Promise<Array<main.EntryOutline>>;
+
 export function GetFlattenedVariables(arg1:string):Promise<Record<string, string>>;
 
+export function GetLayeredVariables(arg1:string):Promise<Array<main.LayeredVariable>>;
+
+export function GetProjectEnvFile():Promise<string>;
+
 export function GetResponseBody(arg1:string,arg2:string):Promise<string>;
 
+export function GetVaultStatus():Promise<main.VaultStatus>;
+
 export function GoUp():Promise<main.CurrentFilesState>;
 
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportDotenv(arg1:Record<string, string>):Promise<main.ImportResult>;
+
+export function ImportHar(arg1:string,arg2:main.HarImportOptions):Promise<main.ImportResult>;
+
+export function ImportPostmanCollection(arg1:string):Promise<main.ImportResult>;
+
+export function ImportPostmanEnvironment(arg1:string):Promise<main.ImportResult>;
+
 export function ListFiles(arg1:string):Promise<Array<main.FileEntry>>;
 
+export function ListHarEntries(arg1:string):Promise<Array<main.HarEntrySummary>>;
+
 export function LoadEnvVariables():Promise<string>;
 
 export function LoadLastOpenedState():Promise<main.CurrentFilesState>;
 
+export function LockVault():Promise<void>;
+
+export function MarkSecret(arg1:string,arg2:string):Promise<void>;
+
+export function MoveEntryDown(arg1:string,arg2:number):Promise<void>;
+
+export function MoveEntryUp(arg1:string,arg2:number):Promise<void>;
+
 export function OpenFile(arg1:string):Promise<main.CurrentFilesState>;
 
+export function ParseHurlContent(arg1:string):Promise<main.HurlFile>;
+
+export function ParseHurlFile(arg1:string):Promise<main.HurlFile>;
+
+export function PreviewFormatHurl(arg1:string):Promise<Array<main.FormatChange>>;
+
+export function PreviewRenameVariable(arg1:string,arg2:string,arg3:string):Promise<main.RenamePreview>;
+
+export function RenameEnvironment(arg1:string,arg2:string):Promise<void>;
+
 export function RenameFile(arg1:string,arg2:string):Promise<void>;
 
 export function RunHurl(arg1:string):Promise<string>;
 
+export function RunHurlEntries(arg1:string,arg2:Array<number>):Promise<string>;
+
 export function RunHurlEntry(arg1:string,arg2:number):Promise<string>;
 
 export function RunHurlWithOptions(arg1:string,arg2:Array<string>):Promise<string>;
@@ -47,3 +137,25 @@ export function SaveEnvVariables(arg1:string):Promise<void>;
 export function SaveFile(arg1:string,arg2:string):Promise<void>;
 
 export function SaveLastOpenedState():Promise<void>;
+
+export function SelectDirectory(arg1:string):Promise<string>;
+
+export function SelectFile(arg1:string,arg2:string):Promise<string>;
+
+export function SetActiveEnvironment(arg1:string):Promise<void>;
+
+export function SetEnvironmentProtection(arg1:string,arg2:boolean,arg3:boolean):Promise<void>;
+
+export function SetSecret(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function SetVariable(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function SetVariableType(arg1:string,arg2:string):Promise<void>;
+
+export function UnlockVault(arg1:string):Promise<void>;
+
+export function UnmarkSecret(arg1:string,arg2:string):Promise<void>;
+
+export function ValidateHurlContent(arg1:string):Promise<Array<main.HurlDiagnostic>>;
+
+export function ValidateHurlFile(arg1:string):Promise<Array<main.HurlDiagnostic>>;
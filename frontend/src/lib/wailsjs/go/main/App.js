@@ -2,30 +2,134 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ApplyFormatHurl(arg1) {
+  return window['go']['main']['App']['ApplyFormatHurl'](arg1);
+}
+
+export function ApplyRenameVariable(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ApplyRenameVariable'](arg1, arg2, arg3);
+}
+
 export function ClearCurrentFile() {
   return window['go']['main']['App']['ClearCurrentFile']();
 }
 
+export function CloneEnvironment(arg1, arg2) {
+  return window['go']['main']['App']['CloneEnvironment'](arg1, arg2);
+}
+
+export function ConvertHttpToHurl(arg1, arg2) {
+  return window['go']['main']['App']['ConvertHttpToHurl'](arg1, arg2);
+}
+
+export function ConvertHurlToHttp(arg1) {
+  return window['go']['main']['App']['ConvertHurlToHttp'](arg1);
+}
+
 export function CreateDir(arg1) {
   return window['go']['main']['App']['CreateDir'](arg1);
 }
 
+export function CreateEnvironment(arg1, arg2) {
+  return window['go']['main']['App']['CreateEnvironment'](arg1, arg2);
+}
+
 export function CreateFile(arg1) {
   return window['go']['main']['App']['CreateFile'](arg1);
 }
 
+export function DeleteEnvironment(arg1) {
+  return window['go']['main']['App']['DeleteEnvironment'](arg1);
+}
+
 export function DeleteFile(arg1) {
   return window['go']['main']['App']['DeleteFile'](arg1);
 }
 
+export function DeleteSecret(arg1, arg2) {
+  return window['go']['main']['App']['DeleteSecret'](arg1, arg2);
+}
+
+export function DeleteVariable(arg1, arg2) {
+  return window['go']['main']['App']['DeleteVariable'](arg1, arg2);
+}
+
+export function DisableEntry(arg1, arg2) {
+  return window['go']['main']['App']['DisableEntry'](arg1, arg2);
+}
+
+export function DuplicateEntry(arg1, arg2) {
+  return window['go']['main']['App']['DuplicateEntry'](arg1, arg2);
+}
+
+export function EnableEntry(arg1, arg2) {
+  return window['go']['main']['App']['EnableEntry'](arg1, arg2);
+}
+
+export function ExportEnvironment(arg1, arg2) {
+  return window['go']['main']['App']['ExportEnvironment'](arg1, arg2);
+}
+
+export function ExportPostmanCollection(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportPostmanCollection'](arg1, arg2, arg3);
+}
+
+export function ExtractEntry(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExtractEntry'](arg1, arg2, arg3);
+}
+
+export function FindDefinition(arg1, arg2, arg3) {
+  return window['go']['main']['App']['FindDefinition'](arg1, arg2, arg3);
+}
+
+export function FindReferences(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['FindReferences'](arg1, arg2, arg3, arg4);
+}
+
+export function FindUnusedVariables(arg1, arg2) {
+  return window['go']['main']['App']['FindUnusedVariables'](arg1, arg2);
+}
+
+export function FormatHurl(arg1) {
+  return window['go']['main']['App']['FormatHurl'](arg1);
+}
+
+export function GenerateFromOpenAPI(arg1, arg2) {
+  return window['go']['main']['App']['GenerateFromOpenAPI'](arg1, arg2);
+}
+
 export function GetActiveEnvironment() {
   return window['go']['main']['App']['GetActiveEnvironment']();
 }
 
+export function GetAuditLog(arg1) {
+  return window['go']['main']['App']['GetAuditLog'](arg1);
+}
+
+export function GetCompletions(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetCompletions'](arg1, arg2, arg3);
+}
+
+export function GetCompletionsForContent(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetCompletionsForContent'](arg1, arg2, arg3, arg4);
+}
+
 export function GetCurrentFilesState() {
   return window['go']['main']['App']['GetCurrentFilesState']();
 }
 
+export function GetEnvSchema() {
+  return window['go']['main']['App']['GetEnvSchema']();
+}
+
+export function GetEnvironmentProtection(arg1) {
+  return window['go']['main']['App']['GetEnvironmentProtection'](arg1);
+}
+
+export function GetEnvironments() {
+  return window['go']['main']['App']['GetEnvironments']();
+}
+
 export function GetExistingReport(arg1) {
   return window['go']['main']['App']['GetExistingReport'](arg1);
 }
@@ -34,14 +138,30 @@ export function GetFileContent(arg1) {
   return window['go']['main']['App']['GetFileContent'](arg1);
 }
 
+export function GetFileOutline(arg1) {
+  return window['go']['main']['App']['GetFileOutline'](arg1);
+}
+
 export function GetFlattenedVariables(arg1) {
   return window['go']['main']['App']['GetFlattenedVariables'](arg1);
 }
 
+export function GetLayeredVariables(arg1) {
+  return window['go']['main']['App']['GetLayeredVariables'](arg1);
+}
+
+export function GetProjectEnvFile() {
+  return window['go']['main']['App']['GetProjectEnvFile']();
+}
+
 export function GetResponseBody(arg1, arg2) {
   return window['go']['main']['App']['GetResponseBody'](arg1, arg2);
 }
 
+export function GetVaultStatus() {
+  return window['go']['main']['App']['GetVaultStatus']();
+}
+
 export function GoUp() {
   return window['go']['main']['App']['GoUp']();
 }
@@ -50,10 +170,30 @@ export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportDotenv(arg1) {
+  return window['go']['main']['App']['ImportDotenv'](arg1);
+}
+
+export function ImportHar(arg1, arg2) {
+  return window['go']['main']['App']['ImportHar'](arg1, arg2);
+}
+
+export function ImportPostmanCollection(arg1) {
+  return window['go']['main']['App']['ImportPostmanCollection'](arg1);
+}
+
+export function ImportPostmanEnvironment(arg1) {
+  return window['go']['main']['App']['ImportPostmanEnvironment'](arg1);
+}
+
 export function ListFiles(arg1) {
   return window['go']['main']['App']['ListFiles'](arg1);
 }
 
+export function ListHarEntries(arg1) {
+  return window['go']['main']['App']['ListHarEntries'](arg1);
+}
+
 export function LoadEnvVariables() {
   return window['go']['main']['App']['LoadEnvVariables']();
 }
@@ -62,10 +202,46 @@ export function LoadLastOpenedState() {
   return window['go']['main']['App']['LoadLastOpenedState']();
 }
 
+export function LockVault() {
+  return window['go']['main']['App']['LockVault']();
+}
+
+export function MarkSecret(arg1, arg2) {
+  return window['go']['main']['App']['MarkSecret'](arg1, arg2);
+}
+
+export function MoveEntryDown(arg1, arg2) {
+  return window['go']['main']['App']['MoveEntryDown'](arg1, arg2);
+}
+
+export function MoveEntryUp(arg1, arg2) {
+  return window['go']['main']['App']['MoveEntryUp'](arg1, arg2);
+}
+
 export function OpenFile(arg1) {
   return window['go']['main']['App']['OpenFile'](arg1);
 }
 
+export function ParseHurlContent(arg1) {
+  return window['go']['main']['App']['ParseHurlContent'](arg1);
+}
+
+export function ParseHurlFile(arg1) {
+  return window['go']['main']['App']['ParseHurlFile'](arg1);
+}
+
+export function PreviewFormatHurl(arg1) {
+  return window['go']['main']['App']['PreviewFormatHurl'](arg1);
+}
+
+export function PreviewRenameVariable(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PreviewRenameVariable'](arg1, arg2, arg3);
+}
+
+export function RenameEnvironment(arg1, arg2) {
+  return window['go']['main']['App']['RenameEnvironment'](arg1, arg2);
+}
+
 export function RenameFile(arg1, arg2) {
   return window['go']['main']['App']['RenameFile'](arg1, arg2);
 }
@@ -74,6 +250,10 @@ export function RunHurl(arg1) {
   return window['go']['main']['App']['RunHurl'](arg1);
 }
 
+export function RunHurlEntries(arg1, arg2) {
+  return window['go']['main']['App']['RunHurlEntries'](arg1, arg2);
+}
+
 export function RunHurlEntry(arg1, arg2) {
   return window['go']['main']['App']['RunHurlEntry'](arg1, arg2);
 }
@@ -93,3 +273,47 @@ export function SaveFile(arg1, arg2) {
 export function SaveLastOpenedState() {
   return window['go']['main']['App']['SaveLastOpenedState']();
 }
+
+export function SelectDirectory(arg1) {
+  return window['go']['main']['App']['SelectDirectory'](arg1);
+}
+
+export function SelectFile(arg1, arg2) {
+  return window['go']['main']['App']['SelectFile'](arg1, arg2);
+}
+
+export function SetActiveEnvironment(arg1) {
+  return window['go']['main']['App']['SetActiveEnvironment'](arg1);
+}
+
+export function SetEnvironmentProtection(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetEnvironmentProtection'](arg1, arg2, arg3);
+}
+
+export function SetSecret(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetSecret'](arg1, arg2, arg3);
+}
+
+export function SetVariable(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetVariable'](arg1, arg2, arg3);
+}
+
+export function SetVariableType(arg1, arg2) {
+  return window['go']['main']['App']['SetVariableType'](arg1, arg2);
+}
+
+export function UnlockVault(arg1) {
+  return window['go']['main']['App']['UnlockVault'](arg1);
+}
+
+export function UnmarkSecret(arg1, arg2) {
+  return window['go']['main']['App']['UnmarkSecret'](arg1, arg2);
+}
+
+export function ValidateHurlContent(arg1) {
+  return window['go']['main']['App']['ValidateHurlContent'](arg1);
+}
+
+export function ValidateHurlFile(arg1) {
+  return window['go']['main']['App']['ValidateHurlFile'](arg1);
+}
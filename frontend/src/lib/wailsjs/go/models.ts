@@ -1,5 +1,120 @@
 export namespace main {
 	
+	export class AuditEntry {
+	    // Go type: time
+	    time: any;
+	    user: string;
+	    environment: string;
+	    file: string;
+	    entries?: number[];
+	    outcome: string;
+	    reason?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.user = source["user"];
+	        this.environment = source["environment"];
+	        this.file = source["file"];
+	        this.entries = source["entries"];
+	        this.outcome = source["outcome"];
+	        this.reason = source["reason"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CompletionItem {
+	    label: string;
+	    kind: string;
+	    detail?: string;
+	    insertText: string;
+	    startColumn: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompletionItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.kind = source["kind"];
+	        this.detail = source["detail"];
+	        this.insertText = source["insertText"];
+	        this.startColumn = source["startColumn"];
+	    }
+	}
+	export class ConversionIssue {
+	    line: number;
+	    construct: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConversionIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.construct = source["construct"];
+	        this.message = source["message"];
+	    }
+	}
+	export class ConversionResult {
+	    outputPath: string;
+	    environment?: string;
+	    variables?: Record<string, string>;
+	    issues: ConversionIssue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConversionResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.outputPath = source["outputPath"];
+	        this.environment = source["environment"];
+	        this.variables = source["variables"];
+	        this.issues = this.convertValues(source["issues"], ConversionIssue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class FileEntry {
 	    name: string;
 	    path: string;
@@ -71,6 +186,845 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class EntryOutline {
+	    index: number;
+	    name: string;
+	    method: string;
+	    url: string;
+	    resolvedUrl: string;
+	    startLine: number;
+	    endLine: number;
+	    asserts: number;
+	    captures: number;
+	    status: string;
+	    time: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EntryOutline(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.name = source["name"];
+	        this.method = source["method"];
+	        this.url = source["url"];
+	        this.resolvedUrl = source["resolvedUrl"];
+	        this.startLine = source["startLine"];
+	        this.endLine = source["endLine"];
+	        this.asserts = source["asserts"];
+	        this.captures = source["captures"];
+	        this.status = source["status"];
+	        this.time = source["time"];
+	    }
+	}
+	export class EnvironmentProtection {
+	    blockMutations?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnvironmentProtection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.blockMutations = source["blockMutations"];
+	    }
+	}
+	export class ExportResult {
+	    collectionPath: string;
+	    environmentPath?: string;
+	    requests: number;
+	    issues: ConversionIssue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.collectionPath = source["collectionPath"];
+	        this.environmentPath = source["environmentPath"];
+	        this.requests = source["requests"];
+	        this.issues = this.convertValues(source["issues"], ConversionIssue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class FormatChange {
+	    path: string;
+	    diff: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FormatChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.diff = source["diff"];
+	        this.error = source["error"];
+	    }
+	}
+	export class HarEntrySummary {
+	    index: number;
+	    method: string;
+	    url: string;
+	    status: number;
+	    mimeType: string;
+	    isStatic: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new HarEntrySummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.method = source["method"];
+	        this.url = source["url"];
+	        this.status = source["status"];
+	        this.mimeType = source["mimeType"];
+	        this.isStatic = source["isStatic"];
+	    }
+	}
+	export class HarImportOptions {
+	    entries: number[];
+	    excludeStatic: boolean;
+	    hostVariable: string;
+	    assertStatus: boolean;
+	    fileName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HarImportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = source["entries"];
+	        this.excludeStatic = source["excludeStatic"];
+	        this.hostVariable = source["hostVariable"];
+	        this.assertStatus = source["assertStatus"];
+	        this.fileName = source["fileName"];
+	    }
+	}
+	export class Position {
+	    line: number;
+	    column: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Position(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.column = source["column"];
+	    }
+	}
+	export class Span {
+	    start: Position;
+	    end: Position;
+	
+	    static createFrom(source: any = {}) {
+	        return new Span(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = this.convertValues(source["start"], Position);
+	        this.end = this.convertValues(source["end"], Position);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlFilter {
+	    name: string;
+	    args?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.args = source["args"];
+	    }
+	}
+	export class HurlQuery {
+	    type: string;
+	    arg?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.arg = source["arg"];
+	    }
+	}
+	export class HurlAssert {
+	    query: HurlQuery;
+	    filters?: HurlFilter[];
+	    not?: boolean;
+	    predicate: string;
+	    value?: string;
+	    span: Span;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlAssert(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.query = this.convertValues(source["query"], HurlQuery);
+	        this.filters = this.convertValues(source["filters"], HurlFilter);
+	        this.not = source["not"];
+	        this.predicate = source["predicate"];
+	        this.value = source["value"];
+	        this.span = this.convertValues(source["span"], Span);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlBody {
+	    kind: string;
+	    lang?: string;
+	    text: string;
+	    span: Span;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlBody(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.lang = source["lang"];
+	        this.text = source["text"];
+	        this.span = this.convertValues(source["span"], Span);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlCapture {
+	    name: string;
+	    query: HurlQuery;
+	    filters?: HurlFilter[];
+	    redact?: boolean;
+	    span: Span;
+	    nameSpan: Span;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlCapture(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.query = this.convertValues(source["query"], HurlQuery);
+	        this.filters = this.convertValues(source["filters"], HurlFilter);
+	        this.redact = source["redact"];
+	        this.span = this.convertValues(source["span"], Span);
+	        this.nameSpan = this.convertValues(source["nameSpan"], Span);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlComment {
+	    text: string;
+	    span: Span;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlComment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.span = this.convertValues(source["span"], Span);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlDiagnostic {
+	    severity: string;
+	    line: number;
+	    column: number;
+	    endLine: number;
+	    endColumn: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlDiagnostic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.severity = source["severity"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.endLine = source["endLine"];
+	        this.endColumn = source["endColumn"];
+	        this.message = source["message"];
+	    }
+	}
+	export class HurlResponse {
+	    span: Span;
+	    version: string;
+	    status: string;
+	    headers: HurlKeyValue[];
+	    sections: HurlSection[];
+	    body?: HurlBody;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.span = this.convertValues(source["span"], Span);
+	        this.version = source["version"];
+	        this.status = source["status"];
+	        this.headers = this.convertValues(source["headers"], HurlKeyValue);
+	        this.sections = this.convertValues(source["sections"], HurlSection);
+	        this.body = this.convertValues(source["body"], HurlBody);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlSection {
+	    name: string;
+	    span: Span;
+	    items?: HurlKeyValue[];
+	    captures?: HurlCapture[];
+	    asserts?: HurlAssert[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlSection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.span = this.convertValues(source["span"], Span);
+	        this.items = this.convertValues(source["items"], HurlKeyValue);
+	        this.captures = this.convertValues(source["captures"], HurlCapture);
+	        this.asserts = this.convertValues(source["asserts"], HurlAssert);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlKeyValue {
+	    key: string;
+	    value: string;
+	    span: Span;
+	    keySpan: Span;
+	    valueSpan: Span;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlKeyValue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.value = source["value"];
+	        this.span = this.convertValues(source["span"], Span);
+	        this.keySpan = this.convertValues(source["keySpan"], Span);
+	        this.valueSpan = this.convertValues(source["valueSpan"], Span);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlRequest {
+	    span: Span;
+	    method: string;
+	    url: string;
+	    urlSpan: Span;
+	    headers: HurlKeyValue[];
+	    sections: HurlSection[];
+	    body?: HurlBody;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.span = this.convertValues(source["span"], Span);
+	        this.method = source["method"];
+	        this.url = source["url"];
+	        this.urlSpan = this.convertValues(source["urlSpan"], Span);
+	        this.headers = this.convertValues(source["headers"], HurlKeyValue);
+	        this.sections = this.convertValues(source["sections"], HurlSection);
+	        this.body = this.convertValues(source["body"], HurlBody);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlEntry {
+	    index: number;
+	    span: Span;
+	    comments: HurlComment[];
+	    request: HurlRequest;
+	    response?: HurlResponse;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.span = this.convertValues(source["span"], Span);
+	        this.comments = this.convertValues(source["comments"], HurlComment);
+	        this.request = this.convertValues(source["request"], HurlRequest);
+	        this.response = this.convertValues(source["response"], HurlResponse);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlParseError {
+	    message: string;
+	    span: Span;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlParseError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.message = source["message"];
+	        this.span = this.convertValues(source["span"], Span);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlVariableUsage {
+	    name: string;
+	    span: Span;
+	    entry: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlVariableUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.span = this.convertValues(source["span"], Span);
+	        this.entry = source["entry"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HurlFile {
+	    entries: HurlEntry[];
+	    variables: HurlVariableUsage[];
+	    errors: HurlParseError[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HurlFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = this.convertValues(source["entries"], HurlEntry);
+	        this.variables = this.convertValues(source["variables"], HurlVariableUsage);
+	        this.errors = this.convertValues(source["errors"], HurlParseError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+	
+	
+	
+	
+	export class ImportResult {
+	    created: string[];
+	    environments: string[];
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.created = source["created"];
+	        this.environments = source["environments"];
+	        this.warnings = source["warnings"];
+	    }
+	}
+	export class LayeredVariable {
+	    name: string;
+	    value: string;
+	    resolved: string;
+	    layer: string;
+	    scope: string;
+	    environment?: string;
+	    path: string;
+	    secret: boolean;
+	    type: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LayeredVariable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.value = source["value"];
+	        this.resolved = source["resolved"];
+	        this.layer = source["layer"];
+	        this.scope = source["scope"];
+	        this.environment = source["environment"];
+	        this.path = source["path"];
+	        this.secret = source["secret"];
+	        this.type = source["type"];
+	    }
+	}
+	
+	export class RenameChange {
+	    path: string;
+	    diff: string;
+	    occurrences: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RenameChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.diff = source["diff"];
+	        this.occurrences = source["occurrences"];
+	    }
+	}
+	export class RenamePreview {
+	    oldName: string;
+	    newName: string;
+	    changes: RenameChange[];
+	    conflicts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RenamePreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.oldName = source["oldName"];
+	        this.newName = source["newName"];
+	        this.changes = this.convertValues(source["changes"], RenameChange);
+	        this.conflicts = source["conflicts"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SymbolLocation {
+	    path: string;
+	    span: Span;
+	    kind: string;
+	    name: string;
+	    environment?: string;
+	    value?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SymbolLocation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.span = this.convertValues(source["span"], Span);
+	        this.kind = source["kind"];
+	        this.name = source["name"];
+	        this.environment = source["environment"];
+	        this.value = source["value"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class VaultStatus {
+	    exists: boolean;
+	    unlocked: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new VaultStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.exists = source["exists"];
+	        this.unlocked = source["unlocked"];
+	    }
+	}
 
 }
 
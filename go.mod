@@ -2,7 +2,12 @@ module hurlstudio
 
 go 1.23
 
-require github.com/wailsapp/wails/v2 v2.10.2
+require (
+	github.com/fsnotify/fsnotify v1.9.0
+	github.com/wailsapp/wails/v2 v2.10.2
+	golang.org/x/crypto v0.33.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/bep/debounce v1.2.1 // indirect
@@ -28,7 +33,6 @@ require (
 	github.com/valyala/fasttemplate v1.2.2 // indirect
 	github.com/wailsapp/go-webview2 v1.0.19 // indirect
 	github.com/wailsapp/mimetype v1.4.1 // indirect
-	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// harLog is the subset of a HAR 1.2 archive used by the importer
+type harLog struct {
+	Log struct {
+		Version string     `json:"version"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	ResourceType    string      `json:"_resourceType,omitempty"`
+}
+
+type harRequest struct {
+	Method   string       `json:"method"`
+	URL      string       `json:"url"`
+	Headers  []harNameVal `json:"headers"`
+	PostData *harPostData `json:"postData,omitempty"`
+}
+
+type harResponse struct {
+	Status  int          `json:"status"`
+	Headers []harNameVal `json:"headers"`
+	Content struct {
+		MimeType string `json:"mimeType"`
+	} `json:"content"`
+}
+
+type harNameVal struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string       `json:"mimeType"`
+	Text     string       `json:"text"`
+	Params   []harNameVal `json:"params,omitempty"`
+}
+
+// HarEntrySummary describes one recorded request so the user can choose which to import
+type HarEntrySummary struct {
+	Index    int    `json:"index"`
+	Method   string `json:"method"`
+	URL      string `json:"url"`
+	Status   int    `json:"status"`
+	MimeType string `json:"mimeType"`
+	IsStatic bool   `json:"isStatic"`
+}
+
+// HarImportOptions controls how a HAR archive is converted
+type HarImportOptions struct {
+	// Entries are the indexes of the entries to import, empty means all
+	Entries []int `json:"entries"`
+	// ExcludeStatic skips entries whose response is a static asset
+	ExcludeStatic bool `json:"excludeStatic"`
+	// HostVariable replaces scheme and host with {{HostVariable}} when set
+	HostVariable string `json:"hostVariable"`
+	// AssertStatus adds the recorded status code to every entry
+	AssertStatus bool `json:"assertStatus"`
+	// FileName is the name of the generated file, defaults to the HAR file name
+	FileName string `json:"fileName"`
+}
+
+// harStaticTypes are response content type prefixes treated as static assets
+var harStaticTypes = []string{
+	"image/",
+	"font/",
+	"audio/",
+	"video/",
+	"text/css",
+	"text/javascript",
+	"application/javascript",
+	"application/x-javascript",
+	"application/font",
+	"application/x-font",
+	"application/wasm",
+}
+
+// harSkippedHeaders are request headers that hurl sets itself or that only make sense for the recorded session
+var harSkippedHeaders = map[string]bool{
+	"host":              true,
+	"content-length":    true,
+	"connection":        true,
+	"accept-encoding":   true,
+	"transfer-encoding": true,
+}
+
+// isStaticEntry reports whether a HAR entry looks like a static asset
+func isStaticEntry(entry harEntry) bool {
+	switch entry.ResourceType {
+	case "image", "font", "stylesheet", "script", "media":
+		return true
+	}
+
+	mimeType := strings.ToLower(entry.Response.Content.MimeType)
+	for _, prefix := range harStaticTypes {
+		if strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readHarFile parses a HAR archive
+func readHarFile(harPath string) (*harLog, error) {
+	data, err := os.ReadFile(harPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HAR file %s: %w", harPath, err)
+	}
+
+	var har harLog
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("invalid HAR file: %w", err)
+	}
+	if len(har.Log.Entries) == 0 {
+		return nil, fmt.Errorf("HAR file contains no entries")
+	}
+
+	return &har, nil
+}
+
+// ListHarEntries returns a summary of every entry in a HAR archive
+func (a *App) ListHarEntries(harPath string) ([]HarEntrySummary, error) {
+	har, err := readHarFile(harPath)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]HarEntrySummary, 0, len(har.Log.Entries))
+	for i, entry := range har.Log.Entries {
+		summaries = append(summaries, HarEntrySummary{
+			Index:    i,
+			Method:   entry.Request.Method,
+			URL:      entry.Request.URL,
+			Status:   entry.Response.Status,
+			MimeType: entry.Response.Content.MimeType,
+			IsStatic: isStaticEntry(entry),
+		})
+	}
+
+	return summaries, nil
+}
+
+// ImportHar writes the selected entries of a HAR archive as a single
+// sequential .hurl file in the current directory
+func (a *App) ImportHar(harPath string, options HarImportOptions) (*ImportResult, error) {
+	if a.currentDir == "" {
+		return nil, fmt.Errorf("no current directory set")
+	}
+
+	har, err := readHarFile(harPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.HostVariable != "" && sanitizeVariableName(options.HostVariable) != options.HostVariable {
+		return nil, fmt.Errorf("invalid variable name %q", options.HostVariable)
+	}
+
+	selected := make(map[int]bool)
+	for _, i := range options.Entries {
+		if i < 0 || i >= len(har.Log.Entries) {
+			return nil, fmt.Errorf("entry index %d is out of range", i)
+		}
+		selected[i] = true
+	}
+
+	result := &ImportResult{}
+	hosts := make(map[string]string)
+	var hostOrder []string
+
+	var requests []*hurlRequest
+	for i, entry := range har.Log.Entries {
+		if len(selected) > 0 && !selected[i] {
+			continue
+		}
+		if options.ExcludeStatic && isStaticEntry(entry) {
+			continue
+		}
+
+		req, err := convertHarEntry(entry)
+		if err != nil {
+			result.warnf("entry %d: %v", i, err)
+			continue
+		}
+
+		if origin := harOrigin(entry.Request.URL); options.HostVariable != "" && origin != "" {
+			name, ok := hosts[origin]
+			if !ok {
+				name = options.HostVariable
+				if len(hostOrder) > 0 {
+					name = fmt.Sprintf("%s_%d", options.HostVariable, len(hostOrder)+1)
+				}
+				hosts[origin] = name
+				hostOrder = append(hostOrder, origin)
+			}
+			req.URL = "{{" + name + "}}" + strings.TrimPrefix(req.URL, origin)
+		}
+
+		if options.AssertStatus && entry.Response.Status > 0 {
+			req.Status = entry.Response.Status
+		}
+
+		requests = append(requests, req)
+	}
+
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("no entries left to import")
+	}
+
+	fileName := options.FileName
+	if fileName == "" {
+		fileName = strings.TrimSuffix(filepath.Base(harPath), filepath.Ext(harPath))
+	}
+	fileName = sanitizeFileName(strings.TrimSuffix(fileName, ".hurl")) + ".hurl"
+
+	filePath := uniquePath(a.currentDir, fileName)
+	if err := writeNewFile(filePath, renderHurlFile(requests)); err != nil {
+		return result, err
+	}
+	result.Created = append(result.Created, filePath)
+
+	if len(hostOrder) > 0 {
+		if err := a.addHarHostVariables(hosts, result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// convertHarEntry converts a recorded request to a hurl request
+func convertHarEntry(entry harEntry) (*hurlRequest, error) {
+	src := entry.Request
+	if src.Method == "" || src.URL == "" {
+		return nil, fmt.Errorf("request has no method or URL")
+	}
+
+	req := &hurlRequest{
+		Method: strings.ToUpper(src.Method),
+		URL:    src.URL,
+	}
+
+	isForm := src.PostData != nil && len(src.PostData.Params) > 0 &&
+		strings.HasPrefix(src.PostData.MimeType, "application/x-www-form-urlencoded")
+
+	for _, h := range src.Headers {
+		name := strings.ToLower(h.Name)
+		// HTTP/2 pseudo headers such as :authority are not real headers
+		if strings.HasPrefix(name, ":") || harSkippedHeaders[name] {
+			continue
+		}
+		if isForm && name == "content-type" {
+			continue
+		}
+		req.Headers = append(req.Headers, hurlKeyValue{Key: h.Name, Value: h.Value})
+	}
+
+	if src.PostData != nil {
+		if isForm {
+			for _, p := range src.PostData.Params {
+				name, _ := url.QueryUnescape(p.Name)
+				value, _ := url.QueryUnescape(p.Value)
+				req.Form = append(req.Form, hurlKeyValue{Key: name, Value: value})
+			}
+		} else {
+			req.Body = src.PostData.Text
+		}
+	}
+
+	return req, nil
+}
+
+// harOrigin returns the scheme and host part of a URL
+func harOrigin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// addHarHostVariables adds the host variables to the active environment,
+// leaving variables that already exist untouched
+func (a *App) addHarHostVariables(hosts map[string]string, result *ImportResult) error {
+	return a.updateEnvConfig(func(config *EnvConfig) error {
+		vars, ok := config.Environments[config.ActiveEnvironment]
+		if !ok || vars == nil {
+			vars = make(map[string]string)
+			config.Environments[config.ActiveEnvironment] = vars
+		}
+
+		for origin, name := range hosts {
+			if secret, err := isSecret(config.ActiveEnvironment, name); err != nil {
+				return err
+			} else if secret {
+				result.warnf("%s is a secret of %s, set it to %s to replay this session", name, config.ActiveEnvironment, origin)
+				continue
+			}
+			if existing, ok := vars[name]; ok {
+				if existing != origin {
+					result.warnf("variable %s already exists in %s, set it to %s to replay this session", name, config.ActiveEnvironment, origin)
+				}
+				continue
+			}
+			vars[name] = origin
+		}
+		result.Environments = append(result.Environments, config.ActiveEnvironment)
+		return nil
+	})
+}
@@ -0,0 +1,634 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ConversionIssue describes a construct that could not be converted
+type ConversionIssue struct {
+	Line      int    `json:"line"`
+	Construct string `json:"construct"`
+	Message   string `json:"message"`
+}
+
+// ConversionResult is returned by the .http <-> .hurl converters
+type ConversionResult struct {
+	OutputPath  string            `json:"outputPath"`
+	Environment string            `json:"environment,omitempty"`
+	Variables   map[string]string `json:"variables,omitempty"`
+	Issues      []ConversionIssue `json:"issues"`
+}
+
+// issue records a construct that could not be converted
+func (r *ConversionResult) issue(line int, construct string, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, ConversionIssue{
+		Line:      line,
+		Construct: construct,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+var (
+	httpMethodLineRegex = regexp.MustCompile(`^([A-Z]+)\s+(\S.*?)(?:\s+HTTP/[0-9.]+)?\s*$`)
+	httpURLLineRegex    = regexp.MustCompile(`^(?:https?://|\{\{)\S*(?:\s+HTTP/[0-9.]+)?\s*$`)
+	httpFileVarRegex    = regexp.MustCompile(`^@([A-Za-z_][\w.-]*)\s*=\s*(.*?)\s*$`)
+	httpMetaRegex       = regexp.MustCompile(`^(?:#|//)\s*@([\w-]+)\s*(.*?)\s*$`)
+	httpHeaderRegex     = regexp.MustCompile(`^([^:\s][^:]*):\s?(.*)$`)
+	httpRequestRefRegex = regexp.MustCompile(`^([\w-]+)\.response\.(body|headers)\.(.+)$`)
+)
+
+// httpDynamicVariables maps REST Client and JetBrains system variables to hurl's generators
+var httpDynamicVariables = map[string]string{
+	"$guid":             "newUuid",
+	"$uuid":             "newUuid",
+	"$random.uuid":      "newUuid",
+	"$randomUUID":       "newUuid",
+	"$isoTimestamp":     "newDate",
+	"$datetime iso8601": "newDate",
+}
+
+// httpFileRequest is one request parsed from a .http file
+type httpFileRequest struct {
+	Line     int
+	Name     string
+	Comments []string
+	Method   string
+	URL      string
+	Headers  []hurlKeyValue
+	Body     []string
+	BodyFile string
+}
+
+// ConvertHttpToHurl converts a JetBrains / VS Code REST Client .http file to a
+// .hurl file next to it. @var = value declarations are added to environment,
+// or to the active environment when environment is empty.
+func (a *App) ConvertHttpToHurl(httpPath string, environment string) (*ConversionResult, error) {
+	content, err := a.GetFileContent(httpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ConversionResult{}
+	requests, variables := parseHTTPFile(content, result)
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("no requests found in %s", httpPath)
+	}
+
+	hurlRequests := convertHTTPRequests(requests, result)
+
+	outputPath := uniquePath(filepath.Dir(httpPath), strings.TrimSuffix(filepath.Base(httpPath), filepath.Ext(httpPath))+".hurl")
+	if err := writeNewFile(outputPath, renderHurlFile(hurlRequests)); err != nil {
+		return nil, err
+	}
+	result.OutputPath = outputPath
+
+	if len(variables) > 0 {
+		err := a.updateEnvConfig(func(config *EnvConfig) error {
+			if environment == "" {
+				environment = config.ActiveEnvironment
+			}
+			vars, ok := config.Environments[environment]
+			if !ok || vars == nil {
+				vars = make(map[string]string)
+				config.Environments[environment] = vars
+			}
+			for k, v := range variables {
+				if secret, err := isSecret(environment, k); err != nil {
+					return err
+				} else if secret {
+					result.issue(0, "@"+k, "%s is a secret of %s, its value was not imported", k, environment)
+					delete(variables, k)
+					continue
+				}
+				vars[k] = v
+			}
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Environment = environment
+		result.Variables = variables
+	}
+
+	return result, nil
+}
+
+// parseHTTPFile splits a .http file into requests and collects its file level variables
+func parseHTTPFile(content string, result *ConversionResult) ([]*httpFileRequest, map[string]string) {
+	variables := make(map[string]string)
+	var requests []*httpFileRequest
+
+	const (
+		stateBefore = iota
+		stateHeaders
+		stateBody
+		stateScript
+	)
+
+	var current *httpFileRequest
+	var pendingName string
+	var pendingComments []string
+	state := stateBefore
+
+	finish := func() {
+		if current != nil {
+			// Trailing blank lines separate requests and are not part of the body
+			for len(current.Body) > 0 && strings.TrimSpace(current.Body[len(current.Body)-1]) == "" {
+				current.Body = current.Body[:len(current.Body)-1]
+			}
+			requests = append(requests, current)
+		}
+		current = nil
+		pendingName = ""
+		pendingComments = nil
+		state = stateBefore
+	}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lineNum := i + 1
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "###") {
+			finish()
+			if title := strings.TrimSpace(strings.TrimLeft(trimmed, "#")); title != "" {
+				pendingComments = append(pendingComments, title)
+			}
+			continue
+		}
+
+		switch state {
+		case stateBefore:
+			if trimmed == "" {
+				continue
+			}
+			if m := httpFileVarRegex.FindStringSubmatch(trimmed); m != nil {
+				variables[sanitizeVariableName(m[1])] = m[2]
+				continue
+			}
+			if m := httpMetaRegex.FindStringSubmatch(trimmed); m != nil {
+				switch m[1] {
+				case "name":
+					pendingName = m[2]
+				case "no-redirect":
+					// hurl does not follow redirects unless asked to
+				default:
+					result.issue(lineNum, "@"+m[1], "request directive @%s is not supported by hurl", m[1])
+				}
+				continue
+			}
+			if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") {
+				pendingComments = append(pendingComments, strings.TrimSpace(strings.TrimLeft(trimmed, "#/")))
+				continue
+			}
+
+			current = &httpFileRequest{Line: lineNum, Name: pendingName, Comments: pendingComments}
+			if m := httpMethodLineRegex.FindStringSubmatch(trimmed); m != nil && !httpURLLineRegex.MatchString(trimmed) {
+				current.Method, current.URL = m[1], m[2]
+			} else {
+				current.Method = "GET"
+				current.URL = strings.Fields(trimmed)[0]
+			}
+			state = stateHeaders
+
+		case stateHeaders:
+			if trimmed == "" {
+				state = stateBody
+				continue
+			}
+			// REST Client allows the query string to continue on following lines
+			if strings.HasPrefix(trimmed, "?") || strings.HasPrefix(trimmed, "&") {
+				current.URL += trimmed
+				continue
+			}
+			if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") {
+				continue
+			}
+			if m := httpHeaderRegex.FindStringSubmatch(trimmed); m != nil {
+				current.Headers = append(current.Headers, hurlKeyValue{Key: strings.TrimSpace(m[1]), Value: m[2]})
+				continue
+			}
+			result.issue(lineNum, "header", "could not parse header line %q", trimmed)
+
+		case stateBody:
+			switch {
+			case strings.HasPrefix(trimmed, "> {%"):
+				result.issue(lineNum, "response handler", "response handler scripts are not supported, use [Captures] and [Asserts] instead")
+				if !strings.HasSuffix(trimmed, "%}") {
+					state = stateScript
+				}
+			case strings.HasPrefix(trimmed, "> "):
+				result.issue(lineNum, "response handler", "response handler script %s is not supported", strings.TrimSpace(trimmed[1:]))
+			case strings.HasPrefix(trimmed, "<> ") || strings.HasPrefix(trimmed, ">> ") || strings.HasPrefix(trimmed, ">>! "):
+				result.issue(lineNum, "response reference", "response output redirection %q is not supported", trimmed)
+			case strings.HasPrefix(trimmed, "< ") && len(current.Body) == 0 && current.BodyFile == "":
+				current.BodyFile = strings.TrimSpace(trimmed[2:])
+			default:
+				if current.BodyFile != "" && trimmed != "" {
+					result.issue(lineNum, "body", "body text after a file include is not supported")
+					continue
+				}
+				current.Body = append(current.Body, line)
+			}
+
+		case stateScript:
+			if strings.HasSuffix(trimmed, "%}") {
+				state = stateBody
+			}
+		}
+	}
+	finish()
+
+	return requests, variables
+}
+
+// convertHTTPRequests converts parsed .http requests to hurl requests. References
+// to other requests' responses become captures on the referenced request.
+func convertHTTPRequests(requests []*httpFileRequest, result *ConversionResult) []*hurlRequest {
+	byName := make(map[string]*hurlRequest)
+	hurlRequests := make([]*hurlRequest, len(requests))
+	for i, src := range requests {
+		hurlRequests[i] = &hurlRequest{Comments: src.Comments}
+		if src.Name != "" {
+			byName[src.Name] = hurlRequests[i]
+		}
+	}
+
+	captured := make(map[string]bool)
+	for i, src := range requests {
+		req := hurlRequests[i]
+
+		mapName := func(name string) string {
+			if mapped, ok := httpDynamicVariables[name]; ok {
+				return mapped
+			}
+			if strings.HasPrefix(name, "$") {
+				result.issue(src.Line, "{{"+name+"}}", "system variable {{%s}} has no hurl equivalent", name)
+				return name
+			}
+
+			m := httpRequestRefRegex.FindStringSubmatch(name)
+			if m == nil {
+				return sanitizeVariableName(name)
+			}
+			target, ok := byName[m[1]]
+			if !ok {
+				result.issue(src.Line, "{{"+name+"}}", "reference to unknown request %s", m[1])
+				return sanitizeVariableName(name)
+			}
+
+			varName := sanitizeVariableName(m[1] + "_" + strings.Trim(invalidVariableChars.ReplaceAllString(m[3], "_"), "_"))
+			if !captured[varName] {
+				query := "header " + quoteHurlString(m[3])
+				if m[2] == "body" {
+					if !strings.HasPrefix(m[3], "$") {
+						result.issue(src.Line, "{{"+name+"}}", "only JSONPath body references can be converted")
+						return sanitizeVariableName(name)
+					}
+					query = "jsonpath " + quoteHurlString(m[3])
+				}
+				target.Captures = append(target.Captures, hurlKeyValue{Key: varName, Value: query})
+				captured[varName] = true
+			}
+			return varName
+		}
+		convert := func(text string) string {
+			return convertTemplates(text, mapName)
+		}
+
+		req.Method = src.Method
+		req.URL = convert(src.URL)
+		for _, h := range src.Headers {
+			req.Headers = append(req.Headers, hurlKeyValue{Key: h.Key, Value: convert(h.Value)})
+		}
+		if src.BodyFile != "" {
+			req.Body = fmt.Sprintf("file,%s;", escapeHurlValue(src.BodyFile))
+		} else if len(src.Body) > 0 {
+			req.Body = convert(strings.Join(src.Body, "\n"))
+		}
+	}
+
+	return hurlRequests
+}
+
+// hurlScanEntry is one entry of a hurl file as seen by the exporters
+type hurlScanEntry struct {
+	Line          int
+	Comments      []string
+	Method        string
+	URL           string
+	Headers       []hurlKeyValue
+	Query         []hurlKeyValue
+	Form          []hurlKeyValue
+	Multipart     []hurlKeyValue
+	MultipartLine int
+	Cookies       []hurlKeyValue
+	Auth          *hurlKeyValue
+	Body          []string
+	File          string
+	Status        string
+	Captures      []hurlScanCapture
+}
+
+// hurlScanCapture is a capture of a scanned entry. Kind is "jsonpath" or
+// "header" when the query can be exported and empty otherwise.
+type hurlScanCapture struct {
+	Line int
+	Name string
+	Kind string
+	Expr string
+}
+
+// ConvertHurlToHttp converts a .hurl file to a REST Client .http file next to it.
+// Asserts and other hurl-only constructs are reported as issues.
+func (a *App) ConvertHurlToHttp(hurlPath string) (*ConversionResult, error) {
+	content, err := a.GetFileContent(hurlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ConversionResult{}
+	entries := scanHurlRequests(content, result)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no entries found in %s", hurlPath)
+	}
+
+	for _, entry := range entries {
+		if len(entry.Multipart) > 0 {
+			result.issue(entry.MultipartLine, "[MultipartFormData]", "multipart bodies are not converted")
+		}
+		for _, c := range entry.Captures {
+			if c.Kind == "" {
+				result.issue(c.Line, "capture", "capture %s uses a query that has no request reference equivalent", c.Name)
+			}
+		}
+	}
+
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return result.Issues[i].Line < result.Issues[j].Line
+	})
+
+	outputPath := uniquePath(filepath.Dir(hurlPath), strings.TrimSuffix(filepath.Base(hurlPath), filepath.Ext(hurlPath))+".http")
+	if err := writeNewFile(outputPath, renderHTTPFile(entries)); err != nil {
+		return nil, err
+	}
+	result.OutputPath = outputPath
+
+	return result, nil
+}
+
+// scanHurlRequests reads the request parts of a hurl file. Response parts
+// are only inspected for the status line and captures.
+func scanHurlRequests(content string, result *ConversionResult) []*hurlScanEntry {
+	file := parseHurl(content)
+	for _, e := range file.Errors {
+		result.issue(e.Span.Start.Line, "syntax", "%s", e.Message)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	lineText := func(span Span) string {
+		return strings.TrimSpace(stripHurlComment(lines[span.Start.Line-1]))
+	}
+
+	var entries []*hurlScanEntry
+	for _, entry := range file.Entries {
+		req := entry.Request
+		current := &hurlScanEntry{
+			Line:    req.Span.Start.Line,
+			Method:  req.Method,
+			URL:     req.URL,
+			Headers: scanKeyValues(req.Headers),
+		}
+		for _, c := range entry.Comments {
+			current.Comments = append(current.Comments, c.Text)
+		}
+
+		for _, section := range req.Sections {
+			items := scanKeyValues(section.Items)
+			switch section.Name {
+			case "QueryStringParams", "Query":
+				current.Query = append(current.Query, items...)
+			case "FormParams", "Form":
+				current.Form = append(current.Form, items...)
+			case "MultipartFormData", "Multipart":
+				current.Multipart = append(current.Multipart, items...)
+				current.MultipartLine = section.Span.Start.Line
+			case "Cookies":
+				current.Cookies = append(current.Cookies, items...)
+			case "BasicAuth":
+				if len(items) > 0 {
+					current.Auth = &items[0]
+				}
+			case "Options":
+				result.issue(section.Span.Start.Line, "[Options]", "hurl options are not converted")
+			}
+		}
+
+		if body := req.Body; body != nil {
+			switch body.Kind {
+			case "file":
+				current.File = body.Text
+			case "base64", "hex":
+				result.issue(body.Span.Start.Line, "body", "%s bodies are not converted", body.Kind)
+			default:
+				current.Body = strings.Split(body.Text, "\n")
+				for len(current.Body) > 0 && strings.TrimSpace(current.Body[len(current.Body)-1]) == "" {
+					current.Body = current.Body[:len(current.Body)-1]
+				}
+			}
+		}
+
+		if resp := entry.Response; resp != nil {
+			current.Status = resp.Status
+			for _, h := range resp.Headers {
+				result.issue(h.Span.Start.Line, "response header", "implicit header assert %q is not converted", lineText(h.Span))
+			}
+			for _, section := range resp.Sections {
+				for _, c := range section.Captures {
+					current.Captures = append(current.Captures, scanCapture(c))
+				}
+				for _, assert := range section.Asserts {
+					result.issue(assert.Span.Start.Line, "assert", "assert %q is not converted", lineText(assert.Span))
+				}
+			}
+		}
+
+		entries = append(entries, current)
+	}
+
+	return entries
+}
+
+// scanKeyValues converts parsed key/value lines to the importer's representation
+func scanKeyValues(items []HurlKeyValue) []hurlKeyValue {
+	var kvs []hurlKeyValue
+	for _, item := range items {
+		kvs = append(kvs, hurlKeyValue{Key: item.Key, Value: item.Value})
+	}
+	return kvs
+}
+
+// scanCapture classifies a capture, only plain jsonpath and header queries can be exported
+func scanCapture(c HurlCapture) hurlScanCapture {
+	capture := hurlScanCapture{Line: c.Span.Start.Line, Name: c.Name, Expr: c.Query.Arg}
+	if len(c.Filters) == 0 && (c.Query.Type == "jsonpath" || c.Query.Type == "header") {
+		capture.Kind = c.Query.Type
+	}
+	return capture
+}
+
+// renderHTTPFile writes hurl entries as a .http file
+func renderHTTPFile(entries []*hurlScanEntry) string {
+	// Captured variables are replaced by references to the capturing request
+	refs := make(map[string]string)
+	var sb strings.Builder
+
+	for i, entry := range entries {
+		replace := func(text string) string {
+			return templateRegex.ReplaceAllStringFunc(text, func(match string) string {
+				name := templateRegex.FindStringSubmatch(match)[1]
+				if ref, ok := refs[name]; ok {
+					return "{{" + ref + "}}"
+				}
+				return match
+			})
+		}
+
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("###")
+		if len(entry.Comments) > 0 {
+			sb.WriteString(" " + entry.Comments[0])
+		}
+		sb.WriteString("\n")
+		for _, comment := range entry.Comments[min(1, len(entry.Comments)):] {
+			sb.WriteString("# " + comment + "\n")
+		}
+		requestName := fmt.Sprintf("request%d", i+1)
+		named := false
+		for _, c := range entry.Captures {
+			if c.Kind != "" && !named {
+				fmt.Fprintf(&sb, "# @name %s\n", requestName)
+				named = true
+			}
+		}
+
+		requestURL := replace(entry.URL)
+		if len(entry.Query) > 0 {
+			var params []string
+			for _, q := range entry.Query {
+				params = append(params, httpQueryEscape(q.Key)+"="+httpQueryEscape(replace(q.Value)))
+			}
+			separator := "?"
+			if strings.Contains(requestURL, "?") {
+				separator = "&"
+			}
+			requestURL += separator + strings.Join(params, "&")
+		}
+		fmt.Fprintf(&sb, "%s %s\n", entry.Method, requestURL)
+
+		for _, h := range entry.Headers {
+			fmt.Fprintf(&sb, "%s: %s\n", h.Key, replace(h.Value))
+		}
+		if entry.Auth != nil {
+			fmt.Fprintf(&sb, "Authorization: Basic %s:%s\n", replace(entry.Auth.Key), replace(entry.Auth.Value))
+		}
+		if len(entry.Cookies) > 0 {
+			var cookies []string
+			for _, c := range entry.Cookies {
+				cookies = append(cookies, c.Key+"="+replace(c.Value))
+			}
+			fmt.Fprintf(&sb, "Cookie: %s\n", strings.Join(cookies, "; "))
+		}
+
+		switch {
+		case len(entry.Form) > 0:
+			sb.WriteString("Content-Type: application/x-www-form-urlencoded\n\n")
+			var params []string
+			for _, f := range entry.Form {
+				params = append(params, httpQueryEscape(f.Key)+"="+httpQueryEscape(replace(f.Value)))
+			}
+			sb.WriteString(strings.Join(params, "&") + "\n")
+		case entry.File != "":
+			fmt.Fprintf(&sb, "\n< %s\n", entry.File)
+		case len(entry.Body) > 0:
+			// hurl sets the JSON content type implicitly, .http files need it spelled out
+			first := strings.TrimSpace(entry.Body[0])
+			if (strings.HasPrefix(first, "{") || strings.HasPrefix(first, "[")) && !hasHeader(entry.Headers, "Content-Type") {
+				sb.WriteString("Content-Type: application/json\n")
+			}
+			sb.WriteString("\n" + replace(strings.Join(entry.Body, "\n")) + "\n")
+		}
+
+		for _, c := range entry.Captures {
+			switch c.Kind {
+			case "jsonpath":
+				refs[c.Name] = requestName + ".response.body." + c.Expr
+			case "header":
+				refs[c.Name] = requestName + ".response.headers." + c.Expr
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// hasHeader reports whether headers contains name, ignoring case
+func hasHeader(headers []hurlKeyValue, name string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h.Key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// httpQueryEscape escapes a query string component, leaving {{templates}} intact
+func httpQueryEscape(value string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range templateRegex.FindAllStringIndex(value, -1) {
+		sb.WriteString(url.QueryEscape(value[last:loc[0]]))
+		sb.WriteString(value[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	sb.WriteString(url.QueryEscape(value[last:]))
+	return sb.String()
+}
+
+// splitHurlKeyValue splits a "key: value" line, honouring escaped colons in the key
+func splitHurlKeyValue(line string) (hurlKeyValue, bool) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case ':':
+			key := unescapeHurlValue(strings.TrimSpace(line[:i]))
+			value := unescapeHurlValue(strings.TrimSpace(line[i+1:]))
+			if key == "" {
+				return hurlKeyValue{}, false
+			}
+			return hurlKeyValue{Key: strings.Trim(key, `"`), Value: value}, true
+		}
+	}
+	return hurlKeyValue{}, false
+}
+
+// unescapeHurlValue reverses escapeHurlKey and escapeHurlValue
+func unescapeHurlValue(value string) string {
+	r := strings.NewReplacer(`\\`, `\`, `\#`, `#`, `\:`, `:`, `\n`, "\n", `\t`, "\t")
+	return r.Replace(value)
+}
+
+// unquoteHurlString reverses the escaping of quoteHurlString
+func unquoteHurlString(value string) string {
+	r := strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n", `\t`, "\t")
+	return r.Replace(value)
+}
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"embed"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -69,68 +75,107 @@ func setupReportDir(filePath string) (string, error) {
 	return reportDir, nil
 }
 
-// readReportFromDir reads the most recent JSON report from the directory
-func readReportFromDir(reportDir string, fallbackOutput []byte) (string, error) {
+// latestReport returns the most recent JSON report of the directory. Without
+// a report hurl stopped before running the file, such as on a syntax error,
+// and its output is returned as the error.
+func latestReport(reportDir string, output []byte) (string, error) {
 	reportFiles, err := filepath.Glob(filepath.Join(reportDir, "*.json"))
 	if err != nil || len(reportFiles) == 0 {
-		// If no JSON report found, return the stdout output
-		return string(fallbackOutput), nil
+		message := strings.TrimSpace(string(output))
+		if message == "" {
+			return "", fmt.Errorf("hurl did not produce a report")
+		}
+		return "", fmt.Errorf("hurl did not produce a report:\n%s", message)
 	}
+	return reportFiles[len(reportFiles)-1], nil
+}
 
-	// Read the most recent report file (last one in sorted list)
-	reportFile := reportFiles[len(reportFiles)-1]
+// readReportFromDir reads the most recent JSON report from the directory
+func readReportFromDir(reportDir string, output []byte) (string, error) {
+	reportFile, err := latestReport(reportDir, output)
+	if err != nil {
+		return "", err
+	}
 	jsonContent, err := os.ReadFile(reportFile)
 	if err != nil {
-		return string(fallbackOutput), nil
+		return "", fmt.Errorf("failed to read report: %w", err)
 	}
-
 	return string(jsonContent), nil
 }
 
 // createVariablesFile creates a temporary variables file from environment variables
-func (a *App) createVariablesFile() (string, error) {
+// Secret variables are not written to the file, they are returned as --secret
+// arguments so hurl redacts them from its output. Values with line breaks,
+// which a variables file cannot hold, are returned as --variable arguments.
+func (a *App) createVariablesFile() (string, []string, error) {
 	// Get active environment
 	activeEnv, err := a.GetActiveEnvironment()
 	if err != nil {
 		// If error, just return empty string (no variables file)
-		return "", nil
+		return "", nil, nil
+	}
+
+	// Get the variables of the active environment, resolved once secrets are decrypted
+	vars, err := a.rawVariables(activeEnv)
+	if err != nil {
+		return "", nil, nil
+	}
+
+	// Decrypt the secrets only now, for this run
+	variableArgs, err := a.secretArgs(activeEnv, vars)
+	if err != nil {
+		return "", nil, err
 	}
 
-	// Get flattened variables for active environment
-	vars, err := a.GetFlattenedVariables(activeEnv)
+	config, err := a.loadEnvConfig()
 	if err != nil {
-		return "", nil
+		return "", nil, err
+	}
+
+	// Write variables in key=value format, typed the way hurl reads them
+	var lines []string
+	for _, key := range slices.Sorted(maps.Keys(vars)) {
+		literal, err := variableLiteral(key, config.Types[key], vars[key])
+		if err != nil {
+			return "", nil, err
+		}
+		if strings.ContainsAny(literal, "\r\n") {
+			variableArgs = append(variableArgs, "--variable", key+"="+literal)
+			continue
+		}
+		lines = append(lines, key+"="+literal)
 	}
 
 	// If no variables, don't create a file
-	if len(vars) == 0 {
-		return "", nil
+	if len(lines) == 0 {
+		return "", variableArgs, nil
 	}
 
 	// Create temp variables file
 	tempFile, err := os.CreateTemp("", "hurl-vars-*.txt")
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp variables file: %w", err)
+		return "", nil, fmt.Errorf("failed to create temp variables file: %w", err)
 	}
 	defer tempFile.Close()
 
-	// Write variables in key=value format
-	var lines []string
-	for key, value := range vars {
-		lines = append(lines, fmt.Sprintf("%s=%s", key, value))
-	}
-
 	content := strings.Join(lines, "\n")
 	if _, err := tempFile.WriteString(content); err != nil {
 		os.Remove(tempFile.Name())
-		return "", fmt.Errorf("failed to write variables file: %w", err)
+		return "", nil, fmt.Errorf("failed to write variables file: %w", err)
 	}
 
-	return tempFile.Name(), nil
+	return tempFile.Name(), variableArgs, nil
 }
 
 // Generates a JSON report in /tmp/hurlstudio/<full-file-path>/
 func (a *App) RunHurl(filePath string) (string, error) {
+	if err := a.validateBeforeRun(filePath, 0); err != nil {
+		return "", err
+	}
+	if err := a.checkProtectedRun(filePath, nil); err != nil {
+		return "", err
+	}
+
 	hurlPath, err := GetHurlPath()
 	if err != nil {
 		return "", err
@@ -142,7 +187,7 @@ func (a *App) RunHurl(filePath string) (string, error) {
 	}
 
 	// Create variables file if needed
-	varsFile, err := a.createVariablesFile()
+	varsFile, variableArgs, err := a.createVariablesFile()
 	if err != nil {
 		return "", err
 	}
@@ -155,6 +200,7 @@ func (a *App) RunHurl(filePath string) (string, error) {
 	if varsFile != "" {
 		args = append(args, "--variables-file", varsFile)
 	}
+	args = append(args, variableArgs...)
 	args = append(args, filePath)
 
 	// Run hurl with JSON report generation
@@ -166,6 +212,10 @@ func (a *App) RunHurl(filePath string) (string, error) {
 
 // RunHurlWithOptions executes a hurl file with custom options
 func (a *App) RunHurlWithOptions(filePath string, options []string) (string, error) {
+	if err := a.checkProtectedRun(filePath, nil); err != nil {
+		return "", err
+	}
+
 	hurlPath, err := GetHurlPath()
 	if err != nil {
 		return "", err
@@ -182,6 +232,13 @@ func (a *App) RunHurlWithOptions(filePath string, options []string) (string, err
 // entryIndex is 1-based (first entry is 1)
 // Uses the same report directory as RunHurl
 func (a *App) RunHurlEntry(filePath string, entryIndex int) (string, error) {
+	if err := a.validateBeforeRun(filePath, entryIndex); err != nil {
+		return "", err
+	}
+	if err := a.checkProtectedRun(filePath, []int{entryIndex}); err != nil {
+		return "", err
+	}
+
 	hurlPath, err := GetHurlPath()
 	if err != nil {
 		return "", err
@@ -193,7 +250,7 @@ func (a *App) RunHurlEntry(filePath string, entryIndex int) (string, error) {
 	}
 
 	// Create variables file if needed
-	varsFile, err := a.createVariablesFile()
+	varsFile, variableArgs, err := a.createVariablesFile()
 	if err != nil {
 		return "", err
 	}
@@ -206,6 +263,7 @@ func (a *App) RunHurlEntry(filePath string, entryIndex int) (string, error) {
 	if varsFile != "" {
 		args = append(args, "--variables-file", varsFile)
 	}
+	args = append(args, variableArgs...)
 	args = append(args, "--from-entry", fmt.Sprintf("%d", entryIndex))
 	args = append(args, "--to-entry", fmt.Sprintf("%d", entryIndex))
 	args = append(args, filePath)
@@ -217,6 +275,197 @@ func (a *App) RunHurlEntry(filePath string, entryIndex int) (string, error) {
 	return readReportFromDir(reportDir, output)
 }
 
+// RunHurlEntries runs a selection of entries that need not be contiguous
+// entryIndexes are 1-based and run in file order, each entry at most once
+// The selected entries are copied to a temporary file, and the report is
+// rewritten so indexes and lines refer to the original file
+func (a *App) RunHurlEntries(filePath string, entryIndexes []int) (string, error) {
+	if len(entryIndexes) == 0 {
+		return "", fmt.Errorf("no entries selected")
+	}
+	selected := slices.Sorted(slices.Values(entryIndexes))
+	selected = slices.Compact(selected)
+	if selected[0] < 1 {
+		return "", fmt.Errorf("entry %d does not exist", selected[0])
+	}
+	if err := a.validateBeforeRun(filePath, selected[len(selected)-1]); err != nil {
+		return "", err
+	}
+	if err := a.checkProtectedRun(filePath, selected); err != nil {
+		return "", err
+	}
+
+	content, err := a.GetFileContent(filePath)
+	if err != nil {
+		return "", err
+	}
+	selection, lineMap, err := selectEntries(content, selected)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp("", "hurlstudio-selection-*.hurl")
+	if err != nil {
+		return "", fmt.Errorf("failed to create selection file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(selection)
+	tmp.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to write selection file: %w", err)
+	}
+
+	hurlPath, err := GetHurlPath()
+	if err != nil {
+		return "", err
+	}
+
+	reportDir, err := setupReportDir(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	// Create variables file if needed
+	varsFile, variableArgs, err := a.createVariablesFile()
+	if err != nil {
+		return "", err
+	}
+	if varsFile != "" {
+		defer os.Remove(varsFile)
+	}
+
+	// Relative file paths in the entries still resolve from the original directory
+	args := []string{"--report-json", reportDir, "--file-root", filepath.Dir(filePath)}
+	if varsFile != "" {
+		args = append(args, "--variables-file", varsFile)
+	}
+	args = append(args, variableArgs...)
+	args = append(args, tmp.Name())
+
+	cmd := exec.Command(hurlPath, args...)
+	output, _ := cmd.CombinedOutput()
+
+	reportFile, err := latestReport(reportDir, output)
+	if err != nil {
+		return "", err
+	}
+	report, err := os.ReadFile(reportFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read report: %w", err)
+	}
+
+	remapped, err := remapSelectionReport(report, filePath, tmp.Name(), selected, lineMap)
+	if err != nil {
+		return "", err
+	}
+	// Later reads of the report, such as the outline, see the original lines
+	if err := os.WriteFile(reportFile, []byte(remapped), 0644); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return remapped, nil
+}
+
+// selectEntries builds a hurl file from the selected 1-based entries, with
+// their comments. lineMap maps each 1-based line of the selection to the line
+// of the original file. Entries the parser did not find are an error.
+func selectEntries(content string, selected []int) (string, []int, error) {
+	lines := strings.Split(content, "\n")
+	blocks := entryBlocks(lines, parseHurl(content))
+
+	var selection []string
+	lineMap := []int{0}
+	for _, index := range selected {
+		if index < 1 || index > len(blocks) {
+			return "", nil, fmt.Errorf("entry %d does not exist, the file has %d entries", index, len(blocks))
+		}
+		b := blocks[index-1]
+		if len(selection) > 0 {
+			selection = append(selection, "")
+			lineMap = append(lineMap, 0)
+		}
+		for n := b.start; n <= b.end; n++ {
+			selection = append(selection, lines[n])
+			lineMap = append(lineMap, n+1)
+		}
+	}
+	return strings.Join(selection, "\n") + "\n", lineMap, nil
+}
+
+// remapSelectionReport rewrites a report of a selection file so entry indexes,
+// lines and file names refer to the original file. Unknown fields are kept.
+func remapSelectionReport(report []byte, filePath string, selectionPath string, selected []int, lineMap []int) (string, error) {
+	var files []map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(report))
+	dec.UseNumber()
+	if err := dec.Decode(&files); err != nil {
+		return "", fmt.Errorf("failed to parse report: %w", err)
+	}
+
+	mapLine := func(value interface{}) interface{} {
+		n, ok := value.(json.Number)
+		if !ok {
+			return value
+		}
+		line, err := n.Int64()
+		if err != nil || line < 1 || int(line) >= len(lineMap) {
+			return value
+		}
+		return lineMap[line]
+	}
+
+	// Lines in error messages look like "--> /tmp/selection.hurl:12:5"
+	locationRegex := regexp.MustCompile(regexp.QuoteMeta(selectionPath) + `:(\d+)`)
+	mapMessage := func(message string) string {
+		return locationRegex.ReplaceAllStringFunc(message, func(location string) string {
+			line, _ := strconv.Atoi(locationRegex.FindStringSubmatch(location)[1])
+			if line < 1 || line >= len(lineMap) {
+				return filePath + location[len(selectionPath):]
+			}
+			return fmt.Sprintf("%s:%d", filePath, lineMap[line])
+		})
+	}
+
+	for _, file := range files {
+		file["filename"] = filePath
+		entries, _ := file["entries"].([]interface{})
+		for _, e := range entries {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if n, ok := entry["index"].(json.Number); ok {
+				if i, err := n.Int64(); err == nil && i >= 1 && int(i) <= len(selected) {
+					entry["index"] = selected[i-1]
+				}
+			}
+			entry["line"] = mapLine(entry["line"])
+			if curl, ok := entry["curl_cmd"].(string); ok {
+				entry["curl_cmd"] = strings.ReplaceAll(curl, selectionPath, filePath)
+			}
+
+			asserts, _ := entry["asserts"].([]interface{})
+			for _, a := range asserts {
+				assert, ok := a.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				assert["line"] = mapLine(assert["line"])
+				if message, ok := assert["message"].(string); ok {
+					assert["message"] = mapMessage(message)
+				}
+			}
+		}
+	}
+
+	var remapped bytes.Buffer
+	enc := json.NewEncoder(&remapped)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(files); err != nil {
+		return "", fmt.Errorf("failed to marshal report: %w", err)
+	}
+	return strings.TrimSuffix(remapped.String(), "\n"), nil
+}
+
 // GetExistingReport checks if a report already exists for the given file path
 // Returns the report JSON content if found, empty string if not found
 func (a *App) GetExistingReport(filePath string) (string, error) {
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSelectEntries(t *testing.T) {
+	content := "# first\nGET https://a\nHTTP 200\n\nGET https://b\n\n# third\nPOST https://c\n{\"x\": 1}\n"
+
+	tests := []struct {
+		name     string
+		content  string
+		selected []int
+		want     string
+		lineMap  []int
+		err      string
+	}{
+		{
+			name:     "single entry with its comment",
+			content:  content,
+			selected: []int{3},
+			want:     "# third\nPOST https://c\n{\"x\": 1}\n",
+			lineMap:  []int{0, 7, 8, 9},
+		},
+		{
+			name:     "entries that are not contiguous",
+			content:  content,
+			selected: []int{1, 3},
+			want:     "# first\nGET https://a\nHTTP 200\n\n# third\nPOST https://c\n{\"x\": 1}\n",
+			lineMap:  []int{0, 1, 2, 3, 0, 7, 8, 9},
+		},
+		{
+			name:     "entry after the last one",
+			content:  content,
+			selected: []int{4},
+			err:      "entry 4 does not exist, the file has 3 entries",
+		},
+		{
+			name:     "entry the parser did not find",
+			content:  "GET https://a\nnot a request\n",
+			selected: []int{1, 3},
+			err:      "entry 3 does not exist, the file has 1 entries",
+		},
+		{
+			name:     "entry zero",
+			content:  content,
+			selected: []int{0},
+			err:      "entry 0 does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, lineMap, err := selectEntries(tt.content, tt.selected)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("selection = %q, want %q", got, tt.want)
+			}
+			if !slices.Equal(lineMap, tt.lineMap) {
+				t.Errorf("lineMap = %v, want %v", lineMap, tt.lineMap)
+			}
+		})
+	}
+}
+
+func TestReadReportFromDir(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		output string
+		want   string
+		err    string
+	}{
+		{name: "report", report: `[{"entries":[]}]`, output: "ignored", want: `[{"entries":[]}]`},
+		{name: "syntax error", output: "error: Parsing method\n  --> a.hurl:1:1\n", err: "hurl did not produce a report:\nerror: Parsing method"},
+		{name: "no output", err: "hurl did not produce a report"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.report != "" {
+				if err := os.WriteFile(filepath.Join(dir, "report.json"), []byte(tt.report), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := readReportFromDir(dir, []byte(tt.output))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				if got != "" {
+					t.Errorf("report = %q, want none", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("report = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,929 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Position is a 1-based line and column in a hurl file. Columns count characters.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Span is the range covered by a node. End is exclusive, matching Monaco ranges.
+type Span struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// HurlFile is the parsed form of a hurl file
+type HurlFile struct {
+	Entries   []HurlEntry         `json:"entries"`
+	Variables []HurlVariableUsage `json:"variables"`
+	Errors    []HurlParseError    `json:"errors"`
+}
+
+// HurlEntry is one request and its optional response
+type HurlEntry struct {
+	// Index is 1-based, as used by hurl's --from-entry and --to-entry
+	Index    int           `json:"index"`
+	Span     Span          `json:"span"`
+	Comments []HurlComment `json:"comments"`
+	Request  HurlRequest   `json:"request"`
+	Response *HurlResponse `json:"response,omitempty"`
+}
+
+// HurlComment is a comment line preceding an entry
+type HurlComment struct {
+	Text string `json:"text"`
+	Span Span   `json:"span"`
+}
+
+// HurlRequest is the request part of an entry
+type HurlRequest struct {
+	Span     Span           `json:"span"`
+	Method   string         `json:"method"`
+	URL      string         `json:"url"`
+	URLSpan  Span           `json:"urlSpan"`
+	Headers  []HurlKeyValue `json:"headers"`
+	Sections []HurlSection  `json:"sections"`
+	Body     *HurlBody      `json:"body,omitempty"`
+}
+
+// HurlResponse is the response part of an entry
+type HurlResponse struct {
+	Span     Span           `json:"span"`
+	Version  string         `json:"version"`
+	Status   string         `json:"status"`
+	Headers  []HurlKeyValue `json:"headers"`
+	Sections []HurlSection  `json:"sections"`
+	Body     *HurlBody      `json:"body,omitempty"`
+}
+
+// HurlKeyValue is a header or a key/value section line, with escapes decoded
+type HurlKeyValue struct {
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Span      Span   `json:"span"`
+	KeySpan   Span   `json:"keySpan"`
+	ValueSpan Span   `json:"valueSpan"`
+}
+
+// HurlSection is a [Section] block. Captures and Asserts are filled for the
+// response sections of that name, Items for every other section.
+type HurlSection struct {
+	Name     string         `json:"name"`
+	Span     Span           `json:"span"`
+	Items    []HurlKeyValue `json:"items,omitempty"`
+	Captures []HurlCapture  `json:"captures,omitempty"`
+	Asserts  []HurlAssert   `json:"asserts,omitempty"`
+}
+
+// HurlQuery is the query part of a capture or assert, such as jsonpath "$.id"
+type HurlQuery struct {
+	Type string `json:"type"`
+	Arg  string `json:"arg,omitempty"`
+}
+
+// HurlFilter is a filter applied to a query result, such as nth 0
+type HurlFilter struct {
+	Name string   `json:"name"`
+	Args []string `json:"args,omitempty"`
+}
+
+// HurlCapture is a line of a [Captures] section
+type HurlCapture struct {
+	Name     string       `json:"name"`
+	Query    HurlQuery    `json:"query"`
+	Filters  []HurlFilter `json:"filters,omitempty"`
+	Redact   bool         `json:"redact,omitempty"`
+	Span     Span         `json:"span"`
+	NameSpan Span         `json:"nameSpan"`
+}
+
+// HurlAssert is a line of an [Asserts] section
+type HurlAssert struct {
+	Query     HurlQuery    `json:"query"`
+	Filters   []HurlFilter `json:"filters,omitempty"`
+	Not       bool         `json:"not,omitempty"`
+	Predicate string       `json:"predicate"`
+	Value     string       `json:"value,omitempty"`
+	Span      Span         `json:"span"`
+}
+
+// HurlBody is a request or response body. Kind is one of json, xml,
+// multiline, oneline, base64, hex or file.
+type HurlBody struct {
+	Kind string `json:"kind"`
+	Lang string `json:"lang,omitempty"`
+	Text string `json:"text"`
+	Span Span   `json:"span"`
+}
+
+// HurlVariableUsage is a {{name}} template in the file
+type HurlVariableUsage struct {
+	Name string `json:"name"`
+	Span Span   `json:"span"`
+	// Entry is the 1-based index of the enclosing entry, 0 when outside any entry
+	Entry int `json:"entry"`
+}
+
+// HurlParseError is a syntax error found while parsing
+type HurlParseError struct {
+	Message string `json:"message"`
+	Span    Span   `json:"span"`
+}
+
+// hurlRequestSections and hurlResponseSections are the section names allowed in each part
+var (
+	hurlRequestSections = map[string]bool{
+		"QueryStringParams": true,
+		"Query":             true,
+		"FormParams":        true,
+		"Form":              true,
+		"MultipartFormData": true,
+		"Multipart":         true,
+		"Cookies":           true,
+		"BasicAuth":         true,
+		"Options":           true,
+	}
+	hurlResponseSections = map[string]bool{
+		"Captures": true,
+		"Asserts":  true,
+	}
+)
+
+// hurlQueryArgs maps each query type to whether it takes an argument
+var hurlQueryArgs = map[string]bool{
+	"status":      false,
+	"version":     false,
+	"url":         false,
+	"body":        false,
+	"duration":    false,
+	"sha256":      false,
+	"md5":         false,
+	"bytes":       false,
+	"redirects":   false,
+	"ip":          false,
+	"header":      true,
+	"cookie":      true,
+	"jsonpath":    true,
+	"xpath":       true,
+	"regex":       true,
+	"variable":    true,
+	"certificate": true,
+}
+
+// hurlFilterArgs maps each filter to its number of arguments
+var hurlFilterArgs = map[string]int{
+	"base64Decode":        0,
+	"base64Encode":        0,
+	"base64UrlSafeDecode": 0,
+	"base64UrlSafeEncode": 0,
+	"count":               0,
+	"dateFormat":          1,
+	"daysAfterNow":        0,
+	"daysBeforeNow":       0,
+	"decode":              1,
+	"first":               0,
+	"format":              1,
+	"htmlEscape":          0,
+	"htmlUnescape":        0,
+	"jsonpath":            1,
+	"last":                0,
+	"location":            0,
+	"nth":                 1,
+	"regex":               1,
+	"replace":             2,
+	"replaceRegex":        2,
+	"split":               1,
+	"toDate":              1,
+	"toFloat":             0,
+	"toHex":               0,
+	"toInt":               0,
+	"toString":            0,
+	"urlDecode":           0,
+	"urlEncode":           0,
+	"urlQueryParam":       1,
+	"utf8Decode":          1,
+	"utf8Encode":          1,
+	"xpath":               1,
+}
+
+// hurlPredicateArgs maps each predicate to whether it takes a value
+var hurlPredicateArgs = map[string]bool{
+	"==":           true,
+	"!=":           true,
+	">":            true,
+	">=":           true,
+	"<":            true,
+	"<=":           true,
+	"startsWith":   true,
+	"endsWith":     true,
+	"contains":     true,
+	"includes":     true,
+	"matches":      true,
+	"exists":       false,
+	"isBoolean":    false,
+	"isCollection": false,
+	"isDate":       false,
+	"isEmpty":      false,
+	"isFloat":      false,
+	"isInteger":    false,
+	"isIsoDate":    false,
+	"isIpv4":       false,
+	"isIpv6":       false,
+	"isList":       false,
+	"isNumber":     false,
+	"isObject":     false,
+	"isString":     false,
+	"isUuid":       false,
+}
+
+var (
+	hurlEntryLineRegex   = regexp.MustCompile(`^([A-Z]+)\s+((?:\{\{[^{}]*\}\}|\S)+)$`)
+	hurlStatusRegex      = regexp.MustCompile(`^(HTTP(?:/1\.0|/1\.1|/2|/3)?)\s+(\d{3}|\*)$`)
+	hurlSectionLineRegex = regexp.MustCompile(`^\[([A-Za-z]+)\]$`)
+)
+
+// isHurlEntryLine reports whether a line starts a new entry. HTTP is never a
+// method, so a malformed status line is not mistaken for a request.
+func isHurlEntryLine(text string) bool {
+	m := hurlEntryLineRegex.FindStringSubmatch(text)
+	return m != nil && m[1] != "HTTP"
+}
+
+// hurlParser is a line based parser for the hurl file format
+type hurlParser struct {
+	lines   []string
+	pos     int
+	file    *HurlFile
+	pending []HurlComment
+
+	// bodyLines marks lines that belong to a body, where # does not start a comment
+	bodyLines map[int]bool
+	// entryStarts holds the 0-based first line of each entry
+	entryStarts []int
+}
+
+// parseHurl parses the content of a hurl file. Syntax errors are reported in
+// the returned file rather than stopping the parse.
+func parseHurl(content string) *HurlFile {
+	p := &hurlParser{
+		lines:     strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n"),
+		file:      &HurlFile{Entries: []HurlEntry{}, Variables: []HurlVariableUsage{}, Errors: []HurlParseError{}},
+		bodyLines: make(map[int]bool),
+	}
+	p.parse()
+	p.collectVariables()
+	return p.file
+}
+
+// ParseHurlFile parses a hurl file from disk and returns its entries
+func (a *App) ParseHurlFile(path string) (*HurlFile, error) {
+	content, err := a.GetFileContent(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseHurl(content), nil
+}
+
+// ParseHurlContent parses unsaved hurl content, such as the editor buffer
+func (a *App) ParseHurlContent(content string) *HurlFile {
+	return parseHurl(content)
+}
+
+// EntryAtLine returns the entry whose request or response contains the 1-based line
+func (f *HurlFile) EntryAtLine(line int) *HurlEntry {
+	for i := range f.Entries {
+		entry := &f.Entries[i]
+		if line >= entry.Span.Start.Line && line <= entry.Span.End.Line {
+			return entry
+		}
+	}
+	return nil
+}
+
+func (p *hurlParser) eof() bool {
+	return p.pos >= len(p.lines)
+}
+
+// lineSpan returns the span of the trimmed content of the 0-based line
+func (p *hurlParser) lineSpan(idx int) Span {
+	line := p.lines[idx]
+	trimmed := strings.TrimSpace(line)
+	start := strings.Index(line, trimmed)
+	return Span{
+		Start: Position{Line: idx + 1, Column: columnAt(line, start)},
+		End:   Position{Line: idx + 1, Column: columnAt(line, start+len(trimmed))},
+	}
+}
+
+// columnAt converts a byte offset in line to a 1-based character column
+func columnAt(line string, offset int) int {
+	if offset > len(line) {
+		offset = len(line)
+	}
+	return utf8.RuneCountInString(line[:offset]) + 1
+}
+
+func (p *hurlParser) errorf(span Span, format string, args ...interface{}) {
+	p.file.Errors = append(p.file.Errors, HurlParseError{Message: fmt.Sprintf(format, args...), Span: span})
+}
+
+// content returns the 0-based line with any trailing comment removed and trimmed
+func (p *hurlParser) content(idx int) string {
+	return strings.TrimSpace(stripHurlComment(p.lines[idx]))
+}
+
+func (p *hurlParser) parse() {
+	for {
+		p.skipTrivia()
+		if p.eof() {
+			return
+		}
+
+		if isHurlEntryLine(p.content(p.pos)) {
+			p.parseEntry()
+			continue
+		}
+
+		p.errorf(p.lineSpan(p.pos), "expected a request line such as \"GET https://example.com\", found %q", p.content(p.pos))
+		p.pending = nil
+		// Skip to the next line that can start an entry
+		for p.pos++; !p.eof() && !isHurlEntryLine(p.content(p.pos)); p.pos++ {
+		}
+	}
+}
+
+// skipTrivia skips blank and comment lines, keeping comments as pending entry
+// comments. A blank line ends a comment block, so an entry only gets the
+// comments right above its request line.
+func (p *hurlParser) skipTrivia() {
+	for !p.eof() {
+		trimmed := strings.TrimSpace(p.lines[p.pos])
+		if trimmed == "" {
+			p.pending = nil
+			p.pos++
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			p.pending = append(p.pending, HurlComment{
+				Text: strings.TrimSpace(strings.TrimPrefix(trimmed, "#")),
+				Span: p.lineSpan(p.pos),
+			})
+			p.pos++
+			continue
+		}
+		return
+	}
+}
+
+func (p *hurlParser) parseEntry() {
+	start := p.pos
+	m := hurlEntryLineRegex.FindStringSubmatch(p.content(p.pos))
+	line := p.lines[p.pos]
+	urlOffset := strings.Index(line, m[2])
+
+	entry := HurlEntry{
+		Index:    len(p.file.Entries) + 1,
+		Comments: p.pending,
+		Request: HurlRequest{
+			Method:   m[1],
+			URL:      m[2],
+			Headers:  []HurlKeyValue{},
+			Sections: []HurlSection{},
+			URLSpan: Span{
+				Start: Position{Line: p.pos + 1, Column: columnAt(line, urlOffset)},
+				End:   Position{Line: p.pos + 1, Column: columnAt(line, urlOffset+len(m[2]))},
+			},
+		},
+	}
+	if entry.Comments == nil {
+		entry.Comments = []HurlComment{}
+	}
+	p.pending = nil
+	p.entryStarts = append(p.entryStarts, start)
+	p.pos++
+
+	last := p.parseMessage(&entry.Request.Headers, &entry.Request.Sections, &entry.Request.Body, hurlRequestSections, start)
+	entry.Request.Span = Span{Start: p.lineSpan(start).Start, End: p.lineSpan(last).End}
+
+	if !p.eof() {
+		if sm := hurlStatusRegex.FindStringSubmatch(p.content(p.pos)); sm != nil {
+			responseStart := p.pos
+			response := &HurlResponse{
+				Version:  sm[1],
+				Status:   sm[2],
+				Headers:  []HurlKeyValue{},
+				Sections: []HurlSection{},
+			}
+			p.pending = nil
+			p.pos++
+			last = p.parseMessage(&response.Headers, &response.Sections, &response.Body, hurlResponseSections, responseStart)
+			response.Span = Span{Start: p.lineSpan(responseStart).Start, End: p.lineSpan(last).End}
+			entry.Response = response
+		}
+	}
+
+	entry.Span = Span{Start: entry.Request.Span.Start, End: p.lineSpan(last).End}
+	p.file.Entries = append(p.file.Entries, entry)
+}
+
+// parseMessage parses the headers, sections and body following a request or
+// status line. It stops before the next entry or status line and returns the
+// 0-based index of the last line that belonged to the message.
+func (p *hurlParser) parseMessage(headers *[]HurlKeyValue, sections *[]HurlSection, body **HurlBody, allowed map[string]bool, last int) int {
+	var section *HurlSection
+
+	for !p.eof() {
+		raw := p.lines[p.pos]
+		trimmed := strings.TrimSpace(raw)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			p.skipTrivia()
+			continue
+		}
+
+		text := p.content(p.pos)
+		if isHurlEntryLine(text) {
+			return last
+		}
+		if hurlStatusRegex.MatchString(text) {
+			if allowed["Asserts"] {
+				p.errorf(p.lineSpan(p.pos), "unexpected status line, a response was already given for this entry")
+				p.pos++
+				continue
+			}
+			return last
+		}
+		if strings.HasPrefix(text, "HTTP") && (len(text) == 4 || text[4] == ' ' || text[4] == '/') {
+			p.errorf(p.lineSpan(p.pos), "invalid status line %q, expected HTTP followed by a status code or *", text)
+			p.pos++
+			last = p.pos - 1
+			continue
+		}
+
+		// Comments between the parts of an entry belong to that entry
+		p.pending = nil
+
+		if *body != nil {
+			p.errorf(p.lineSpan(p.pos), "unexpected content after the body: %q", text)
+			p.pos++
+			continue
+		}
+
+		if m := hurlSectionLineRegex.FindStringSubmatch(text); m != nil && !isJSONKeyword(m[1]) {
+			name := m[1]
+			if !allowed[name] {
+				if hurlRequestSections[name] || hurlResponseSections[name] {
+					p.errorf(p.lineSpan(p.pos), "section [%s] is not allowed here", name)
+				} else {
+					p.errorf(p.lineSpan(p.pos), "unknown section [%s]", name)
+				}
+			}
+			*sections = append(*sections, HurlSection{Name: name, Span: p.lineSpan(p.pos)})
+			section = &(*sections)[len(*sections)-1]
+			last = p.pos
+			p.pos++
+			continue
+		}
+
+		if isBodyStart(text) {
+			*body = p.parseBody()
+			last = p.pos - 1
+			continue
+		}
+
+		span := p.lineSpan(p.pos)
+		switch {
+		case section == nil:
+			if kv, ok := p.parseKeyValue(text, span); ok {
+				*headers = append(*headers, kv)
+			}
+		case section.Name == "Captures":
+			if capture, ok := p.parseCapture(text, span); ok {
+				section.Captures = append(section.Captures, capture)
+			}
+		case section.Name == "Asserts":
+			if assert, ok := p.parseAssert(text, span); ok {
+				section.Asserts = append(section.Asserts, assert)
+			}
+		default:
+			if kv, ok := p.parseKeyValue(text, span); ok {
+				section.Items = append(section.Items, kv)
+			}
+		}
+		if section != nil {
+			section.Span.End = span.End
+		}
+		last = p.pos
+		p.pos++
+	}
+
+	return last
+}
+
+// isJSONKeyword reports whether a bracketed word is really a JSON array such as [true]
+func isJSONKeyword(word string) bool {
+	return word == "true" || word == "false" || word == "null"
+}
+
+// isBodyStart reports whether a line starts a request or response body
+func isBodyStart(text string) bool {
+	if hurlSectionLineRegex.MatchString(text) {
+		m := hurlSectionLineRegex.FindStringSubmatch(text)
+		return isJSONKeyword(m[1])
+	}
+	for _, prefix := range []string{"{", "[", "\"", "<", "`", "base64,", "hex,", "file,"} {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBody parses the body starting at the current line and advances past it
+func (p *hurlParser) parseBody() *HurlBody {
+	start := p.pos
+	text := p.content(p.pos)
+	raw := strings.TrimSpace(p.lines[p.pos])
+	body := &HurlBody{}
+
+	finish := func(end int) *HurlBody {
+		for i := start; i <= end; i++ {
+			p.bodyLines[i] = true
+		}
+		body.Span = Span{Start: p.lineSpan(start).Start, End: p.lineSpan(end).End}
+		p.pos = end + 1
+		return body
+	}
+
+	switch {
+	case strings.HasPrefix(raw, "```"):
+		rest := strings.TrimPrefix(raw, "```")
+		if len(rest) >= 3 && strings.HasSuffix(rest, "```") {
+			body.Kind = "oneline"
+			body.Text = strings.TrimSuffix(rest, "```")
+			return finish(start)
+		}
+		body.Kind = "multiline"
+		body.Lang = strings.TrimSpace(rest)
+		var content []string
+		for i := start + 1; i < len(p.lines); i++ {
+			if strings.TrimSpace(p.lines[i]) == "```" {
+				body.Text = strings.Join(content, "\n")
+				return finish(i)
+			}
+			content = append(content, p.lines[i])
+		}
+		p.errorf(p.lineSpan(start), "unterminated multiline string, expected a closing ```")
+		body.Text = strings.Join(content, "\n")
+		return finish(len(p.lines) - 1)
+
+	case strings.HasPrefix(raw, "`"):
+		body.Kind = "oneline"
+		if len(text) < 2 || !strings.HasSuffix(text, "`") {
+			p.errorf(p.lineSpan(start), "unterminated oneline string, expected a closing `")
+		}
+		body.Text = strings.TrimSuffix(strings.TrimPrefix(text, "`"), "`")
+		return finish(start)
+
+	case strings.HasPrefix(text, "base64,"), strings.HasPrefix(text, "hex,"), strings.HasPrefix(text, "file,"):
+		kind := text[:strings.Index(text, ",")]
+		body.Kind = kind
+		if !strings.HasSuffix(text, ";") {
+			p.errorf(p.lineSpan(start), "%s body must end with ;", kind)
+		}
+		body.Text = strings.TrimSuffix(strings.TrimPrefix(text, kind+","), ";")
+		if kind == "file" {
+			body.Text = unescapeHurlValue(strings.TrimSpace(body.Text))
+		}
+		return finish(start)
+
+	case strings.HasPrefix(raw, "<"):
+		// XML runs until the next section, response or entry, blank lines
+		// included. Trailing blank and comment lines are not part of it.
+		body.Kind = "xml"
+		end := start
+		for i := start + 1; i < len(p.lines); i++ {
+			next := strings.TrimSpace(p.lines[i])
+			if hurlSectionLineRegex.MatchString(next) || hurlStatusRegex.MatchString(next) || isHurlEntryLine(next) {
+				break
+			}
+			if next != "" && !strings.HasPrefix(next, "#") {
+				end = i
+			}
+		}
+		body.Text = strings.Join(p.lines[start:end+1], "\n")
+		return finish(end)
+	}
+
+	// JSON runs until its brackets are balanced
+	body.Kind = "json"
+	depth := 0
+	inString := false
+	escaped := false
+	for i := start; i < len(p.lines); i++ {
+		for _, c := range p.lines[i] {
+			switch {
+			case escaped:
+				escaped = false
+			case inString && c == '\\':
+				escaped = true
+			case c == '"':
+				inString = !inString
+			case inString:
+			case c == '{' || c == '[':
+				depth++
+			case c == '}' || c == ']':
+				depth--
+			}
+		}
+		if depth <= 0 && !inString {
+			body.Text = strings.Join(p.lines[start:i+1], "\n")
+			return finish(i)
+		}
+	}
+	p.errorf(p.lineSpan(start), "unterminated JSON body")
+	body.Text = strings.Join(p.lines[start:], "\n")
+	return finish(len(p.lines) - 1)
+}
+
+// parseKeyValue parses a "key: value" line
+func (p *hurlParser) parseKeyValue(text string, span Span) (HurlKeyValue, bool) {
+	kv, ok := splitHurlKeyValue(text)
+	if !ok {
+		p.errorf(span, "expected \"key: value\", found %q", text)
+		return HurlKeyValue{}, false
+	}
+	keySpan, valueSpan := p.keyValueSpans(p.pos)
+	return HurlKeyValue{Key: kv.Key, Value: kv.Value, Span: span, KeySpan: keySpan, ValueSpan: valueSpan}, true
+}
+
+// keyValueSpans returns the spans of the key and the value of a "key: value" line
+func (p *hurlParser) keyValueSpans(idx int) (Span, Span) {
+	line := p.lines[idx]
+	content := strings.TrimRight(stripHurlComment(line), " \t")
+	start := len(content) - len(strings.TrimLeft(content, " \t"))
+	pos := func(offset int) Position {
+		return Position{Line: idx + 1, Column: columnAt(line, offset)}
+	}
+
+	colon := -1
+	for i := start; i < len(content) && colon < 0; i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case ':':
+			colon = i
+		}
+	}
+	if colon < 0 {
+		return Span{Start: pos(start), End: pos(len(content))}, Span{Start: pos(len(content)), End: pos(len(content))}
+	}
+
+	keyEnd := start + len(strings.TrimRight(content[start:colon], " \t"))
+	valueStart := colon + 1
+	for valueStart < len(content) && (content[valueStart] == ' ' || content[valueStart] == '\t') {
+		valueStart++
+	}
+	return Span{Start: pos(start), End: pos(keyEnd)}, Span{Start: pos(valueStart), End: pos(len(content))}
+}
+
+// parseCapture parses a "name: query filters" line
+func (p *hurlParser) parseCapture(text string, span Span) (HurlCapture, bool) {
+	kv, ok := splitHurlKeyValue(text)
+	if !ok {
+		p.errorf(span, "expected \"name: query\", found %q", text)
+		return HurlCapture{}, false
+	}
+
+	tokens, _ := tokenizeHurl(kv.Value)
+	nameSpan, _ := p.keyValueSpans(p.pos)
+	capture := HurlCapture{Name: kv.Key, Span: span, NameSpan: nameSpan}
+	if n := len(tokens); n > 0 && tokens[n-1] == "redact" {
+		capture.Redact = true
+		tokens = tokens[:n-1]
+	}
+
+	query, next, err := parseHurlQuery(tokens, 0)
+	if err != nil {
+		p.errorf(span, "capture %s: %v", kv.Key, err)
+		return HurlCapture{}, false
+	}
+	filters, next, err := parseHurlFilters(tokens, next)
+	if err != nil {
+		p.errorf(span, "capture %s: %v", kv.Key, err)
+		return HurlCapture{}, false
+	}
+	if next < len(tokens) {
+		p.errorf(span, "capture %s: unexpected %q", kv.Key, tokens[next])
+		return HurlCapture{}, false
+	}
+
+	capture.Query = query
+	capture.Filters = filters
+	return capture, true
+}
+
+// parseAssert parses a "query filters [not] predicate value" line
+func (p *hurlParser) parseAssert(text string, span Span) (HurlAssert, bool) {
+	tokens, offsets := tokenizeHurl(text)
+
+	query, next, err := parseHurlQuery(tokens, 0)
+	if err != nil {
+		p.errorf(span, "assert: %v", err)
+		return HurlAssert{}, false
+	}
+	filters, next, err := parseHurlFilters(tokens, next)
+	if err != nil {
+		p.errorf(span, "assert: %v", err)
+		return HurlAssert{}, false
+	}
+
+	assert := HurlAssert{Query: query, Filters: filters, Span: span}
+	if next < len(tokens) && tokens[next] == "not" {
+		assert.Not = true
+		next++
+	}
+	if next >= len(tokens) {
+		p.errorf(span, "assert: missing predicate after %s", query.Type)
+		return HurlAssert{}, false
+	}
+
+	predicate := tokens[next]
+	takesValue, ok := hurlPredicateArgs[predicate]
+	if !ok {
+		p.errorf(span, "assert: unknown predicate %q", predicate)
+		return HurlAssert{}, false
+	}
+	assert.Predicate = predicate
+	next++
+
+	if takesValue {
+		if next >= len(tokens) {
+			p.errorf(span, "assert: predicate %s needs a value", predicate)
+			return HurlAssert{}, false
+		}
+		assert.Value = text[offsets[next]:]
+	} else if next < len(tokens) {
+		p.errorf(span, "assert: predicate %s does not take a value", predicate)
+		return HurlAssert{}, false
+	}
+
+	return assert, true
+}
+
+// parseHurlQuery parses a query starting at tokens[i] and returns the index after it
+func parseHurlQuery(tokens []string, i int) (HurlQuery, int, error) {
+	if i >= len(tokens) {
+		return HurlQuery{}, i, fmt.Errorf("missing query")
+	}
+	queryType := tokens[i]
+	takesArg, ok := hurlQueryArgs[queryType]
+	if !ok {
+		return HurlQuery{}, i, fmt.Errorf("unknown query %q", queryType)
+	}
+	if !takesArg {
+		return HurlQuery{Type: queryType}, i + 1, nil
+	}
+	if i+1 >= len(tokens) || !isQuotedToken(tokens[i+1]) {
+		return HurlQuery{}, i, fmt.Errorf("query %s needs a quoted argument", queryType)
+	}
+	return HurlQuery{Type: queryType, Arg: unquoteHurlToken(tokens[i+1])}, i + 2, nil
+}
+
+// parseHurlFilters parses the filters starting at tokens[i] and returns the index after them
+func parseHurlFilters(tokens []string, i int) ([]HurlFilter, int, error) {
+	var filters []HurlFilter
+	for i < len(tokens) {
+		argc, ok := hurlFilterArgs[tokens[i]]
+		if !ok {
+			break
+		}
+		if i+argc >= len(tokens) {
+			return nil, i, fmt.Errorf("filter %s needs %d argument(s)", tokens[i], argc)
+		}
+		filter := HurlFilter{Name: tokens[i]}
+		for j := 1; j <= argc; j++ {
+			filter.Args = append(filter.Args, unquoteHurlToken(tokens[i+j]))
+		}
+		filters = append(filters, filter)
+		i += argc + 1
+	}
+	return filters, i, nil
+}
+
+// tokenizeHurl splits a capture or assert into tokens, keeping quoted
+// strings, regex literals and templates together. It also returns the byte
+// offset of each token.
+func tokenizeHurl(text string) ([]string, []int) {
+	var tokens []string
+	var offsets []int
+	i := 0
+	for i < len(text) {
+		if text[i] == ' ' || text[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		switch text[i] {
+		case '"', '/', '`':
+			quote := text[i]
+			i++
+			for i < len(text) && text[i] != quote {
+				if text[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+		default:
+			for i < len(text) && text[i] != ' ' && text[i] != '\t' {
+				if strings.HasPrefix(text[i:], "{{") {
+					if end := strings.Index(text[i:], "}}"); end >= 0 {
+						i += end + 2
+						continue
+					}
+				}
+				i++
+			}
+		}
+		if i > len(text) {
+			i = len(text)
+		}
+		tokens = append(tokens, text[start:i])
+		offsets = append(offsets, start)
+	}
+	return tokens, offsets
+}
+
+// isQuotedToken reports whether a token is a string or regex literal
+func isQuotedToken(token string) bool {
+	if len(token) < 2 {
+		return false
+	}
+	first, last := token[0], token[len(token)-1]
+	return (first == '"' || first == '/' || first == '`') && last == first
+}
+
+// unquoteHurlToken decodes a quoted token, leaving other tokens unchanged
+func unquoteHurlToken(token string) string {
+	if len(token) >= 2 && token[0] == '"' && token[len(token)-1] == '"' {
+		return unquoteHurlString(token[1 : len(token)-1])
+	}
+	return token
+}
+
+// stripHurlComment removes a trailing # comment from a line, ignoring
+// escaped and quoted # characters
+func stripHurlComment(line string) string {
+	inQuotes := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			inQuotes = !inQuotes
+		case '#':
+			if !inQuotes {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// collectVariables records every {{name}} template outside comments
+func (p *hurlParser) collectVariables() {
+	entry := 0
+	for idx, line := range p.lines {
+		for entry < len(p.entryStarts) && p.entryStarts[entry] <= idx {
+			entry++
+		}
+
+		text := line
+		if !p.bodyLines[idx] {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			text = stripHurlComment(line)
+		}
+
+		for _, loc := range templateRegex.FindAllStringSubmatchIndex(text, -1) {
+			p.file.Variables = append(p.file.Variables, HurlVariableUsage{
+				Name: text[loc[2]:loc[3]],
+				Span: Span{
+					Start: Position{Line: idx + 1, Column: columnAt(line, loc[0])},
+					End:   Position{Line: idx + 1, Column: columnAt(line, loc[1])},
+				},
+				Entry: entry,
+			})
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestParseHurl(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		entries []string
+		errors  []string
+	}{
+		{
+			name:    "entries with comments and responses",
+			content: "# login\n# as admin\nPOST https://a/login\n{\"user\": \"admin\"}\nHTTP 200\n\nGET https://a/me\nAuthorization: Bearer {{token}}\nHTTP/2 *\n",
+			entries: []string{"1 POST https://a/login [login as admin] 200", "2 GET https://a/me [] *"},
+		},
+		{
+			name:    "adjacent entries",
+			content: "GET https://a\nHTTP 200\nGET https://b\n",
+			entries: []string{"1 GET https://a [] 200", "2 GET https://b [] -"},
+		},
+		{
+			name:    "comment separated from the request by a blank line",
+			content: "# header\n\n# entry\nDELETE https://a\n",
+			entries: []string{"1 DELETE https://a [entry] -"},
+		},
+		{
+			name:    "invalid line",
+			content: "GET https://a\nnot valid\n",
+			entries: []string{"1 GET https://a [] -"},
+			errors:  []string{`2: expected "key: value", found "not valid"`},
+		},
+		{
+			name:    "empty file",
+			content: "\n# only a comment\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := parseHurl(tt.content)
+
+			var entries []string
+			for _, entry := range file.Entries {
+				var comments []string
+				for _, c := range entry.Comments {
+					comments = append(comments, c.Text)
+				}
+				status := "-"
+				if entry.Response != nil {
+					status = entry.Response.Status
+				}
+				entries = append(entries, fmt.Sprintf("%d %s %s %v %s", entry.Index, entry.Request.Method, entry.Request.URL, comments, status))
+			}
+			if !slices.Equal(entries, tt.entries) {
+				t.Errorf("entries = %q, want %q", entries, tt.entries)
+			}
+
+			var errors []string
+			for _, e := range file.Errors {
+				errors = append(errors, fmt.Sprintf("%d: %s", e.Span.Start.Line, e.Message))
+			}
+			if !slices.Equal(errors, tt.errors) {
+				t.Errorf("errors = %q, want %q", errors, tt.errors)
+			}
+		})
+	}
+}
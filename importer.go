@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ImportResult summarises what an importer created and what it had to skip
+type ImportResult struct {
+	Created      []string `json:"created"`
+	Environments []string `json:"environments"`
+	Warnings     []string `json:"warnings"`
+}
+
+// warnf records a non-fatal problem encountered during an import
+func (r *ImportResult) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// hurlKeyValue is a single key/value line in a hurl section
+type hurlKeyValue struct {
+	Key   string
+	Value string
+}
+
+// hurlRequest is an intermediate representation of one hurl entry that
+// importers fill in and render to text
+type hurlRequest struct {
+	Comments  []string
+	Method    string
+	URL       string
+	Headers   []hurlKeyValue
+	Query     []hurlKeyValue
+	Form      []hurlKeyValue
+	Multipart []hurlKeyValue
+	BasicAuth *hurlKeyValue
+	Body      string
+
+	// Status is the expected response status, 0 means any status
+	Status   int
+	Captures []hurlKeyValue
+	Asserts  []string
+}
+
+// render returns the hurl text for the request
+func (r *hurlRequest) render() string {
+	var sb strings.Builder
+
+	for _, comment := range r.Comments {
+		for _, line := range strings.Split(comment, "\n") {
+			sb.WriteString(strings.TrimRight("# "+line, " "))
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString(r.Method)
+	sb.WriteString(" ")
+	sb.WriteString(strings.ReplaceAll(r.URL, " ", "%20"))
+	sb.WriteString("\n")
+
+	for _, h := range r.Headers {
+		fmt.Fprintf(&sb, "%s: %s\n", escapeHurlKey(h.Key), escapeHurlValue(h.Value))
+	}
+
+	writeSection := func(name string, values []hurlKeyValue) {
+		if len(values) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "[%s]\n", name)
+		for _, kv := range values {
+			fmt.Fprintf(&sb, "%s: %s\n", escapeHurlKey(kv.Key), escapeHurlValue(kv.Value))
+		}
+	}
+
+	if r.BasicAuth != nil {
+		writeSection("BasicAuth", []hurlKeyValue{*r.BasicAuth})
+	}
+	writeSection("QueryStringParams", r.Query)
+	writeSection("FormParams", r.Form)
+
+	// Multipart values are already hurl expressions (e.g. file,data.bin;)
+	if len(r.Multipart) > 0 {
+		sb.WriteString("[MultipartFormData]\n")
+		for _, kv := range r.Multipart {
+			fmt.Fprintf(&sb, "%s: %s\n", escapeHurlKey(kv.Key), kv.Value)
+		}
+	}
+
+	if r.Body != "" {
+		sb.WriteString(formatHurlBody(r.Body))
+	}
+
+	if r.Status == 0 && len(r.Captures) == 0 && len(r.Asserts) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("\n")
+	if r.Status != 0 {
+		fmt.Fprintf(&sb, "HTTP %d\n", r.Status)
+	} else {
+		sb.WriteString("HTTP *\n")
+	}
+
+	if len(r.Captures) > 0 {
+		sb.WriteString("[Captures]\n")
+		for _, kv := range r.Captures {
+			fmt.Fprintf(&sb, "%s: %s\n", kv.Key, kv.Value)
+		}
+	}
+
+	if len(r.Asserts) > 0 {
+		sb.WriteString("[Asserts]\n")
+		for _, assert := range r.Asserts {
+			sb.WriteString(assert)
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// formatHurlBody renders a request body, keeping JSON bodies as JSON
+// literals and wrapping anything else in a multiline string
+func formatHurlBody(body string) string {
+	trimmed := strings.TrimSpace(body)
+	if strings.HasPrefix(trimmed, "file,") {
+		return trimmed + "\n"
+	}
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(templatePlaceholders(trimmed))) {
+		return trimmed + "\n"
+	}
+	return "```\n" + strings.TrimRight(body, "\n") + "\n```\n"
+}
+
+// renderHurlFile joins several requests into the content of a hurl file
+func renderHurlFile(requests []*hurlRequest) string {
+	parts := make([]string, 0, len(requests))
+	for _, r := range requests {
+		parts = append(parts, r.render())
+	}
+	return strings.Join(parts, "\n")
+}
+
+// escapeHurlKey escapes the characters that cannot appear unescaped in a hurl key
+func escapeHurlKey(key string) string {
+	r := strings.NewReplacer(`\`, `\\`, `#`, `\#`, `:`, `\:`)
+	return r.Replace(key)
+}
+
+// escapeHurlValue escapes the characters that cannot appear unescaped in a hurl value
+func escapeHurlValue(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, `#`, `\#`, "\n", `\n`)
+	return r.Replace(value)
+}
+
+// quoteHurlString returns value as a double quoted hurl string
+func quoteHurlString(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	return `"` + r.Replace(value) + `"`
+}
+
+var templateRegex = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// templatePlaceholders replaces {{...}} templates with a JSON compatible
+// number so a templated body can still be checked for JSON validity
+func templatePlaceholders(text string) string {
+	return templateRegex.ReplaceAllString(text, "0")
+}
+
+var invalidVariableChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// sanitizeVariableName turns an arbitrary name into a valid hurl variable name
+func sanitizeVariableName(name string) string {
+	name = invalidVariableChars.ReplaceAllString(strings.TrimSpace(name), "_")
+	if name == "" {
+		return "var"
+	}
+	if c := name[0]; !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+		name = "v" + name
+	}
+	return name
+}
+
+// convertTemplates rewrites every {{name}} in text using mapName
+func convertTemplates(text string, mapName func(string) string) string {
+	return templateRegex.ReplaceAllStringFunc(text, func(match string) string {
+		name := templateRegex.FindStringSubmatch(match)[1]
+		return "{{" + mapName(name) + "}}"
+	})
+}
+
+var invalidFileNameChars = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]+`)
+
+// sanitizeFileName turns an arbitrary name into something safe to use as a
+// file or directory name
+func sanitizeFileName(name string) string {
+	name = strings.TrimSpace(invalidFileNameChars.ReplaceAllString(name, "_"))
+	name = strings.Trim(name, ".")
+	if name == "" {
+		return "untitled"
+	}
+	return name
+}
+
+// uniquePath returns a path in dir for name that does not exist yet,
+// adding a numeric suffix before the extension if needed
+func uniquePath(dir string, name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	candidate := filepath.Join(dir, name)
+	for i := 2; ; i++ {
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, i, ext))
+	}
+}
+
+// createImportDir creates dirPath through CreateDir, which works relative to
+// the current directory, and records it in result
+func (a *App) createImportDir(dirPath string, result *ImportResult) error {
+	rel, err := filepath.Rel(a.currentDir, dirPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", dirPath, err)
+	}
+	if err := a.CreateDir(rel); err != nil {
+		return err
+	}
+	result.Created = append(result.Created, dirPath)
+	return nil
+}
+
+// writeNewFile writes content to path, refusing to overwrite an existing file
+func writeNewFile(path string, content string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// readUserEnvConfig reads the user env.json into a fresh EnvConfig that can
+// be modified without touching the cached copy. Callers modifying it hold
+// envWriteMu and write it with writeEnvConfig.
+func (a *App) readUserEnvConfig() (*EnvConfig, error) {
+	configJSON, err := a.LoadEnvVariables()
+	if err != nil {
+		return nil, err
+	}
+
+	var config EnvConfig
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse env.json: %w", err)
+	}
+	if config.Global == nil {
+		config.Global = make(map[string]string)
+	}
+	if config.Environments == nil {
+		config.Environments = make(map[string]map[string]string)
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// osVariableRegex matches ${NAME} references to OS environment variables
+var osVariableRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// referenceRegex matches {{name}} and ${NAME} references along with the
+// backslash escaping them, if any
+var referenceRegex = regexp.MustCompile(`\\?(?:` + templateRegex.String() + `|` + osVariableRegex.String() + `)`)
+
+// variableResolver expands the references of environment values, memoizing
+// each variable and the variables it depends on
+type variableResolver struct {
+	raw      map[string]string
+	literal  map[string]bool
+	resolved map[string]string
+	refs     map[string]map[string]bool
+	visiting map[string]bool
+	stack    []string
+}
+
+// resolveVariables expands {{name}} references to other variables and ${NAME}
+// references to OS environment variables in every value. A reference preceded
+// by a backslash is kept as written, without the backslash. References to
+// unknown variables are left as written, hurl may still define them when the
+// request runs. refs lists the variables each variable depends on, directly
+// or not. A cycle of references is an error.
+func resolveVariables(raw map[string]string) (map[string]string, map[string]map[string]bool, error) {
+	return resolveVariablesWithLiterals(raw, nil)
+}
+
+// resolveVariablesWithLiterals is resolveVariables where the values of the
+// literal variables, such as secrets, are taken as written. They are still
+// substituted into the variables referencing them.
+func resolveVariablesWithLiterals(raw map[string]string, literal map[string]bool) (map[string]string, map[string]map[string]bool, error) {
+	r := &variableResolver{
+		raw:      raw,
+		literal:  literal,
+		resolved: make(map[string]string),
+		refs:     make(map[string]map[string]bool),
+		visiting: make(map[string]bool),
+	}
+	for _, name := range slices.Sorted(maps.Keys(raw)) {
+		if _, err := r.resolve(name); err != nil {
+			return nil, nil, err
+		}
+	}
+	return r.resolved, r.refs, nil
+}
+
+func (r *variableResolver) resolve(name string) (string, error) {
+	if value, ok := r.resolved[name]; ok {
+		return value, nil
+	}
+	if r.literal[name] {
+		r.resolved[name] = r.raw[name]
+		r.refs[name] = make(map[string]bool)
+		return r.raw[name], nil
+	}
+	if r.visiting[name] {
+		start := slices.Index(r.stack, name)
+		cycle := append(slices.Clone(r.stack[start:]), name)
+		return "", fmt.Errorf("variable cycle: %s", strings.Join(cycle, " -> "))
+	}
+	r.visiting[name] = true
+	r.stack = append(r.stack, name)
+	defer func() {
+		delete(r.visiting, name)
+		r.stack = r.stack[:len(r.stack)-1]
+	}()
+
+	// References are replaced in a single pass, so the values substituted
+	// for them are not expanded again
+	refs := make(map[string]bool)
+	var resolveErr error
+	value := referenceRegex.ReplaceAllStringFunc(r.raw[name], func(reference string) string {
+		if escaped, ok := strings.CutPrefix(reference, `\`); ok {
+			return escaped
+		}
+		m := referenceRegex.FindStringSubmatch(reference)
+
+		// Unset OS variables are kept as written so the request shows what is missing
+		if strings.HasPrefix(reference, "$") {
+			if env, ok := os.LookupEnv(m[2]); ok {
+				return env
+			}
+			return reference
+		}
+
+		ref := m[1]
+		if _, ok := r.raw[ref]; !ok || resolveErr != nil {
+			return reference
+		}
+		resolved, err := r.resolve(ref)
+		if err != nil {
+			resolveErr = err
+			return reference
+		}
+		refs[ref] = true
+		for dep := range r.refs[ref] {
+			refs[dep] = true
+		}
+		return resolved
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	r.resolved[name] = value
+	r.refs[name] = refs
+	return value, nil
+}
+
+// escapeReferences escapes the references of value, so resolveVariables
+// keeps them as written
+func escapeReferences(value string) string {
+	return referenceRegex.ReplaceAllStringFunc(value, func(reference string) string {
+		return `\` + reference
+	})
+}
+
+// checkVariableCycles reports the first cycle of references, or of extended
+// environments, in the flattened variables of each environment of config
+func checkVariableCycles(config *EnvConfig) error {
+	if _, _, err := resolveVariables(config.Global); err != nil {
+		return err
+	}
+	for _, env := range slices.Sorted(maps.Keys(config.Environments)) {
+		merged, err := flattenEnvironment(config, env)
+		if err != nil {
+			return err
+		}
+		if _, _, err := resolveVariables(merged); err != nil {
+			return fmt.Errorf("environment %s: %w", env, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestResolveVariables(t *testing.T) {
+	t.Setenv("HURLSTUDIO_TEST_HOST", "os.example.com")
+
+	tests := []struct {
+		name    string
+		raw     map[string]string
+		literal map[string]bool
+		want    map[string]string
+		err     string
+	}{
+		{
+			name: "nested references",
+			raw:  map[string]string{"host": "example.com", "base": "https://{{host}}", "users": "{{base}}/users"},
+			want: map[string]string{"host": "example.com", "base": "https://example.com", "users": "https://example.com/users"},
+		},
+		{
+			name: "OS environment",
+			raw:  map[string]string{"url": "https://${HURLSTUDIO_TEST_HOST}", "unset": "${HURLSTUDIO_TEST_UNSET}"},
+			want: map[string]string{"url": "https://os.example.com", "unset": "${HURLSTUDIO_TEST_UNSET}"},
+		},
+		{
+			name: "escaped and unknown references",
+			raw:  map[string]string{"a": "1", "b": `\{{a}} {{a}} {{token}}`},
+			want: map[string]string{"a": "1", "b": "{{a}} 1 {{token}}"},
+		},
+		{
+			name: "substituted values are not expanded again",
+			raw:  map[string]string{"a": `\{{b}}`, "b": "2", "c": "{{a}}"},
+			want: map[string]string{"a": "{{b}}", "b": "2", "c": "{{b}}"},
+		},
+		{
+			name:    "literal values",
+			raw:     map[string]string{"secret": "p{{a}}ss", "a": "x", "auth": "Basic {{secret}}"},
+			literal: map[string]bool{"secret": true},
+			want:    map[string]string{"secret": "p{{a}}ss", "a": "x", "auth": "Basic p{{a}}ss"},
+		},
+		{
+			name: "cycle",
+			raw:  map[string]string{"a": "{{b}}", "b": "{{c}}", "c": "{{a}}"},
+			err:  "variable cycle: a -> b -> c -> a",
+		},
+		{
+			name: "self reference",
+			raw:  map[string]string{"a": "x{{a}}"},
+			err:  "variable cycle: a -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := resolveVariablesWithLiterals(tt.raw, tt.literal)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("resolveVariables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckVariableCycles(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *EnvConfig
+		err    string
+	}{
+		{
+			name: "no cycle",
+			config: &EnvConfig{
+				Global:       map[string]string{"host": "example.com"},
+				Environments: map[string]map[string]string{"dev": {"url": "https://{{host}}"}},
+			},
+		},
+		{
+			name: "cycle through the global variables",
+			config: &EnvConfig{
+				Global:       map[string]string{"url": "{{base}}/x"},
+				Environments: map[string]map[string]string{"dev": {"base": "{{url}}"}},
+			},
+			err: "environment dev: variable cycle: base -> url -> base",
+		},
+		{
+			name: "cycle of extended environments",
+			config: &EnvConfig{
+				Environments: map[string]map[string]string{"a": {}, "b": {}},
+				Extends:      map[string]string{"a": "b", "b": "a"},
+			},
+			err: "environment cycle: a -> b -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkVariableCycles(tt.config)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SymbolLocation is a place where a variable is defined or used. Kind is one
+// of capture, option, usage, environment or global. Environment names the
+// environment of an environment definition.
+type SymbolLocation struct {
+	Path        string `json:"path"`
+	Span        Span   `json:"span"`
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Environment string `json:"environment,omitempty"`
+	Value       string `json:"value,omitempty"`
+}
+
+// containsPosition reports whether a 1-based line and column fall inside span.
+// The end column is included so a cursor right after a name still matches.
+func containsPosition(span Span, line int, column int) bool {
+	if line < span.Start.Line || line > span.End.Line {
+		return false
+	}
+	if line == span.Start.Line && column < span.Start.Column {
+		return false
+	}
+	if line == span.End.Line && column > span.End.Column {
+		return false
+	}
+	return true
+}
+
+// symbolAt returns the variable at a position, either a {{usage}} or the name
+// of a capture or [Options] variable. The returned usage places the symbol in
+// the file for definition lookups.
+func symbolAt(file *HurlFile, line int, column int) (string, HurlVariableUsage, bool) {
+	for _, usage := range file.Variables {
+		if containsPosition(usage.Span, line, column) {
+			return usage.Name, usage, true
+		}
+	}
+	for _, d := range hurlDefinitions(file) {
+		if containsPosition(d.NameSpan, line, column) {
+			return d.Name, HurlVariableUsage{Name: d.Name, Span: d.NameSpan, Entry: d.Entry}, true
+		}
+	}
+	return "", HurlVariableUsage{}, false
+}
+
+// localDefinition returns the definition a usage resolves to within its file:
+// the closest one in effect, or the first one when the variable is only
+// defined later. ok is false when the file does not define the variable.
+func localDefinition(defs []hurlDefinition, usage HurlVariableUsage) (hurlDefinition, bool) {
+	var found *hurlDefinition
+	for i, d := range defs {
+		if d.Name != usage.Name {
+			continue
+		}
+		if d.NameSpan == usage.Span {
+			return d, true
+		}
+		if d.isDefinedBefore(usage) || found == nil {
+			found = &defs[i]
+		}
+	}
+	if found == nil {
+		return hurlDefinition{}, false
+	}
+	return *found, true
+}
+
+func definitionLocation(path string, d hurlDefinition) SymbolLocation {
+	kind := "capture"
+	if d.Option {
+		kind = "option"
+	}
+	return SymbolLocation{Path: path, Span: d.NameSpan, Kind: kind, Name: d.Name}
+}
+
+// FindDefinition returns where the variable at a 1-based line and column of a
+// hurl file is defined: a capture or option in the file, or its key in env.json.
+// It returns nil when there is no variable at the position or it is undefined.
+func (a *App) FindDefinition(path string, line int, column int) (*SymbolLocation, error) {
+	content, err := a.GetFileContent(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := parseHurl(content)
+	name, usage, ok := symbolAt(file, line, column)
+	if !ok {
+		return nil, nil
+	}
+
+	if d, ok := localDefinition(hurlDefinitions(file), usage); ok {
+		location := definitionLocation(path, d)
+		return &location, nil
+	}
+
+	config, err := a.loadEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+	locations, err := a.environmentLocations(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The active environment overrides the global variables
+	for _, location := range locations {
+		if location.Kind == "environment" && location.Environment == config.ActiveEnvironment {
+			return &location, nil
+		}
+	}
+	for _, location := range locations {
+		if location.Kind == "global" {
+			return &location, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindReferences returns every location of the variable at a 1-based line and
+// column of a hurl file. Captured variables are local to their file, other
+// variables are searched in every .hurl file below dirPath and in env.json.
+func (a *App) FindReferences(dirPath string, path string, line int, column int) ([]SymbolLocation, error) {
+	content, err := a.GetFileContent(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := parseHurl(content)
+	name, usage, ok := symbolAt(file, line, column)
+	if !ok {
+		return []SymbolLocation{}, nil
+	}
+
+	if _, local := localDefinition(hurlDefinitions(file), usage); local {
+		return fileReferences(path, file, name), nil
+	}
+
+	files, err := collectHurlFiles(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	references := fileReferences(path, file, name)
+	for _, other := range files {
+		if filepath.Clean(other) == filepath.Clean(path) {
+			continue
+		}
+		otherContent, err := a.GetFileContent(other)
+		if err != nil {
+			return nil, err
+		}
+		otherFile := parseHurl(otherContent)
+
+		// Files that capture the variable themselves use their own value
+		shadowed := false
+		for _, d := range hurlDefinitions(otherFile) {
+			if d.Name == name {
+				shadowed = true
+				break
+			}
+		}
+		if !shadowed {
+			references = append(references, fileReferences(other, otherFile, name)...)
+		}
+	}
+
+	locations, err := a.environmentLocations(name)
+	if err != nil {
+		return nil, err
+	}
+	return append(references, locations...), nil
+}
+
+// fileReferences returns the definitions and usages of name in a parsed file
+func fileReferences(path string, file *HurlFile, name string) []SymbolLocation {
+	references := []SymbolLocation{}
+	for _, d := range hurlDefinitions(file) {
+		if d.Name == name {
+			references = append(references, definitionLocation(path, d))
+		}
+	}
+	for _, usage := range file.Variables {
+		if usage.Name == name {
+			references = append(references, SymbolLocation{Path: path, Span: usage.Span, Kind: "usage", Name: name})
+		}
+	}
+
+	sort.SliceStable(references, func(i, j int) bool {
+		return references[i].Span.Start.Line < references[j].Span.Start.Line
+	})
+	return references
+}
+
+// environmentLocations returns the keys of the env.json files that define
+// name, the user file before the project file, each with global first and
+// then every environment in name order
+func (a *App) environmentLocations(name string) ([]SymbolLocation, error) {
+	layers, err := a.envLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []SymbolLocation
+	for _, layer := range layers {
+		data, err := os.ReadFile(layer.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", layer.Path, err)
+		}
+		content := string(data)
+		config := layer.Config
+
+		if value, ok := config.Global[name]; ok {
+			if span, ok := jsonKeySpan(content, "global", name); ok {
+				locations = append(locations, SymbolLocation{Path: layer.Path, Span: span, Kind: "global", Name: name, Value: value})
+			}
+		}
+
+		environments := make([]string, 0, len(config.Environments))
+		for env := range config.Environments {
+			environments = append(environments, env)
+		}
+		sort.Strings(environments)
+
+		for _, env := range environments {
+			value, ok := config.Environments[env][name]
+			if !ok {
+				continue
+			}
+			if span, ok := jsonKeySpan(content, "environments", env, name); ok {
+				locations = append(locations, SymbolLocation{Path: layer.Path, Span: span, Kind: "environment", Name: name, Environment: env, Value: value})
+			}
+		}
+	}
+	return locations, nil
+}
+
+// jsonKeySpan returns the span of the quoted key reached by following keyPath
+// through nested JSON objects
+func jsonKeySpan(content string, keyPath ...string) (Span, bool) {
+	type frame struct {
+		object    bool
+		expectKey bool
+		key       string
+	}
+
+	dec := json.NewDecoder(strings.NewReader(content))
+	var stack []*frame
+
+	// valueDone marks the value of the enclosing object key as read
+	valueDone := func() {
+		if len(stack) > 0 && stack[len(stack)-1].object {
+			stack[len(stack)-1].expectKey = true
+		}
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return Span{}, false
+		}
+
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{':
+				stack = append(stack, &frame{object: true, expectKey: true})
+			case '[':
+				stack = append(stack, &frame{})
+			default:
+				stack = stack[:len(stack)-1]
+				valueDone()
+			}
+		case string:
+			top := stack[len(stack)-1]
+			if !top.object || !top.expectKey {
+				valueDone()
+				continue
+			}
+			top.key = t
+			top.expectKey = false
+
+			if len(stack) != len(keyPath) {
+				continue
+			}
+			matches := true
+			for i, f := range stack {
+				if f.key != keyPath[i] {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				quoted, _ := json.Marshal(t)
+				end := int(dec.InputOffset())
+				start := end - len(quoted)
+				return Span{Start: offsetPosition(content, start), End: offsetPosition(content, end)}, true
+			}
+		default:
+			valueDone()
+		}
+	}
+}
+
+// offsetPosition converts a byte offset in content to a 1-based position
+func offsetPosition(content string, offset int) Position {
+	before := content[:offset]
+	line := strings.Count(before, "\n") + 1
+	lineStart := strings.LastIndex(before, "\n") + 1
+	return Position{Line: line, Column: columnAt(content[lineStart:], offset-lineStart)}
+}
@@ -0,0 +1,662 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIMethods lists the operation keys of a path item in the order they are generated
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete", "head", "options", "trace"}
+
+// openAPIMaxDepth limits how deep example generation follows nested schemas
+const openAPIMaxDepth = 8
+
+// openAPIGenerator holds the parsed document and the state of a single generation run
+type openAPIGenerator struct {
+	doc     map[string]interface{}
+	baseVar string
+	result  *ImportResult
+}
+
+// openAPIOperation is one method on one path with its path level parameters merged in
+type openAPIOperation struct {
+	Path   string
+	Method string
+	Op     map[string]interface{}
+	Params []interface{}
+}
+
+// GenerateFromOpenAPI generates a folder of .hurl files from an OpenAPI 3
+// document (JSON or YAML) in the current directory. groupBy is "tag" to write
+// one file per tag or "path" to write one file per path. A base URL variable
+// named after the API is added to the environments in env.json.
+func (a *App) GenerateFromOpenAPI(specPath string, groupBy string) (*ImportResult, error) {
+	if groupBy != "tag" && groupBy != "path" {
+		return nil, fmt.Errorf("invalid grouping %q, expected \"tag\" or \"path\"", groupBy)
+	}
+	if a.currentDir == "" {
+		return nil, fmt.Errorf("no current directory set")
+	}
+
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI document %s: %w", specPath, err)
+	}
+
+	// YAML is a superset of JSON so one decoder handles both formats
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	doc, ok := normalizeYAML(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid OpenAPI document: expected an object at the top level")
+	}
+
+	version := stringField(doc, "openapi")
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, expected 3.x", version)
+	}
+
+	title := stringField(mapField(doc, "info"), "title")
+	if title == "" {
+		title = "openapi"
+	}
+
+	result := &ImportResult{}
+	gen := &openAPIGenerator{
+		doc:     doc,
+		baseVar: sanitizeVariableName(strings.ToLower(title)) + "_base_url",
+		result:  result,
+	}
+
+	groups, order := gen.collectOperations(groupBy)
+	if len(order) == 0 {
+		return nil, fmt.Errorf("OpenAPI document has no operations")
+	}
+
+	rootPath := uniquePath(a.currentDir, sanitizeFileName(title))
+	if err := a.createImportDir(rootPath, result); err != nil {
+		return result, err
+	}
+
+	for _, group := range order {
+		var requests []*hurlRequest
+		for _, op := range groups[group] {
+			requests = append(requests, gen.buildRequest(op))
+		}
+
+		filePath := uniquePath(rootPath, sanitizeFileName(group)+".hurl")
+		if err := writeNewFile(filePath, renderHurlFile(requests)); err != nil {
+			return result, err
+		}
+		result.Created = append(result.Created, filePath)
+	}
+
+	if err := gen.addBaseURL(a); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// collectOperations groups the document's operations by tag or path,
+// returning the groups and their names in a stable order
+func (g *openAPIGenerator) collectOperations(groupBy string) (map[string][]openAPIOperation, []string) {
+	paths := mapField(g.doc, "paths")
+	pathNames := make([]string, 0, len(paths))
+	for p := range paths {
+		pathNames = append(pathNames, p)
+	}
+	sort.Strings(pathNames)
+
+	groups := make(map[string][]openAPIOperation)
+	var order []string
+
+	for _, path := range pathNames {
+		item := g.resolve(paths[path])
+		pathItem, _ := item.(map[string]interface{})
+		commonParams := listField(pathItem, "parameters")
+
+		for _, method := range openAPIMethods {
+			op := mapField(pathItem, method)
+			if op == nil {
+				continue
+			}
+
+			group := pathFileName(path)
+			if groupBy == "tag" {
+				group = "default"
+				if tags := listField(op, "tags"); len(tags) > 0 {
+					if tag, ok := tags[0].(string); ok && tag != "" {
+						group = tag
+					}
+				}
+			}
+
+			if _, ok := groups[group]; !ok {
+				order = append(order, group)
+			}
+			groups[group] = append(groups[group], openAPIOperation{
+				Path:   path,
+				Method: strings.ToUpper(method),
+				Op:     op,
+				Params: append(append([]interface{}{}, commonParams...), listField(op, "parameters")...),
+			})
+		}
+	}
+
+	return groups, order
+}
+
+// pathFileName turns an API path such as /users/{id}/orders into users_id_orders
+func pathFileName(path string) string {
+	name := strings.NewReplacer("{", "", "}", "").Replace(strings.Trim(path, "/"))
+	name = strings.ReplaceAll(name, "/", "_")
+	if name == "" {
+		return "root"
+	}
+	return name
+}
+
+// buildRequest converts an operation to a hurl request
+func (g *openAPIGenerator) buildRequest(op openAPIOperation) *hurlRequest {
+	req := &hurlRequest{Method: op.Method}
+
+	if summary := stringField(op.Op, "summary"); summary != "" {
+		req.Comments = append(req.Comments, summary)
+	}
+	if id := stringField(op.Op, "operationId"); id != "" {
+		req.Comments = append(req.Comments, "operationId: "+id)
+	}
+	if deprecated, _ := op.Op["deprecated"].(bool); deprecated {
+		req.Comments = append(req.Comments, "deprecated")
+	}
+
+	path := op.Path
+	for _, p := range op.Params {
+		param := g.resolveMap(p)
+		name := stringField(param, "name")
+		if name == "" {
+			continue
+		}
+
+		switch stringField(param, "in") {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+name+"}", g.paramValue(param))
+		case "query":
+			required, _ := param["required"].(bool)
+			if required || param["example"] != nil {
+				req.Query = append(req.Query, hurlKeyValue{Key: name, Value: g.paramValue(param)})
+			}
+		case "header":
+			req.Headers = append(req.Headers, hurlKeyValue{Key: name, Value: g.paramValue(param)})
+		case "cookie":
+			req.Headers = append(req.Headers, hurlKeyValue{Key: "Cookie", Value: name + "=" + g.paramValue(param)})
+		}
+	}
+	req.URL = "{{" + g.baseVar + "}}" + path
+
+	g.addSecurity(op.Op, req)
+	g.addRequestBody(op, req)
+	g.addResponseAsserts(op.Op, req)
+
+	return req
+}
+
+// paramValue returns an example value for a parameter, falling back to a
+// variable named after the parameter
+func (g *openAPIGenerator) paramValue(param map[string]interface{}) string {
+	if example, ok := param["example"]; ok {
+		return exampleString(example)
+	}
+	if schema := g.resolveMap(param["schema"]); schema != nil {
+		if example, ok := schema["example"]; ok {
+			return exampleString(example)
+		}
+		if enum := listField(schema, "enum"); len(enum) > 0 {
+			return exampleString(enum[0])
+		}
+	}
+	return "{{" + sanitizeVariableName(stringField(param, "name")) + "}}"
+}
+
+// addSecurity adds auth headers for the operation's security requirement,
+// or the document's global one
+func (g *openAPIGenerator) addSecurity(op map[string]interface{}, req *hurlRequest) {
+	security, ok := op["security"]
+	if !ok {
+		security = g.doc["security"]
+	}
+	requirements, _ := security.([]interface{})
+	if len(requirements) == 0 {
+		return
+	}
+
+	requirement, _ := requirements[0].(map[string]interface{})
+	schemes := mapField(mapField(g.doc, "components"), "securitySchemes")
+	for name := range requirement {
+		scheme := g.resolveMap(schemes[name])
+		if scheme == nil {
+			continue
+		}
+
+		switch stringField(scheme, "type") {
+		case "http":
+			switch strings.ToLower(stringField(scheme, "scheme")) {
+			case "bearer":
+				req.Headers = append(req.Headers, hurlKeyValue{Key: "Authorization", Value: "Bearer {{token}}"})
+			case "basic":
+				req.BasicAuth = &hurlKeyValue{Key: "{{username}}", Value: "{{password}}"}
+			}
+		case "apiKey":
+			key := stringField(scheme, "name")
+			value := "{{" + sanitizeVariableName(key) + "}}"
+			switch stringField(scheme, "in") {
+			case "header":
+				req.Headers = append(req.Headers, hurlKeyValue{Key: key, Value: value})
+			case "query":
+				req.Query = append(req.Query, hurlKeyValue{Key: key, Value: value})
+			}
+		case "oauth2", "openIdConnect":
+			req.Headers = append(req.Headers, hurlKeyValue{Key: "Authorization", Value: "Bearer {{token}}"})
+		}
+	}
+}
+
+// addRequestBody builds an example body from the operation's request body
+func (g *openAPIGenerator) addRequestBody(op openAPIOperation, req *hurlRequest) {
+	body := g.resolveMap(op.Op["requestBody"])
+	content := mapField(body, "content")
+	if len(content) == 0 {
+		return
+	}
+
+	mediaType, media := pickMediaType(content)
+	example := g.mediaExample(media)
+
+	switch {
+	case strings.Contains(mediaType, "json"):
+		if example == nil {
+			g.result.warnf("%s %s: no example or schema for %s body", op.Method, op.Path, mediaType)
+			return
+		}
+		data, err := json.MarshalIndent(example, "", "  ")
+		if err != nil {
+			g.result.warnf("%s %s: failed to build example body: %v", op.Method, op.Path, err)
+			return
+		}
+		if mediaType != "application/json" {
+			req.Headers = append(req.Headers, hurlKeyValue{Key: "Content-Type", Value: mediaType})
+		}
+		req.Body = string(data)
+	case mediaType == "application/x-www-form-urlencoded":
+		req.Form = append(req.Form, exampleFields(example)...)
+	case mediaType == "multipart/form-data":
+		schema := g.resolveMap(media["schema"])
+		props := mapField(schema, "properties")
+		for _, kv := range exampleFields(example) {
+			prop := g.resolveMap(props[kv.Key])
+			if stringField(prop, "format") == "binary" || stringField(prop, "format") == "base64" {
+				kv.Value = fmt.Sprintf("file,%s;", kv.Key)
+				g.result.warnf("%s %s: multipart field %s expects a file", op.Method, op.Path, kv.Key)
+			} else {
+				kv.Value = escapeHurlValue(kv.Value)
+			}
+			req.Multipart = append(req.Multipart, kv)
+		}
+	default:
+		req.Headers = append(req.Headers, hurlKeyValue{Key: "Content-Type", Value: mediaType})
+		if s, ok := example.(string); ok && s != "" {
+			req.Body = s
+		} else {
+			g.result.warnf("%s %s: no example for %s body", op.Method, op.Path, mediaType)
+		}
+	}
+}
+
+// addResponseAsserts adds a status line and basic asserts from the first
+// documented success response
+func (g *openAPIGenerator) addResponseAsserts(op map[string]interface{}, req *hurlRequest) {
+	responses := mapField(op, "responses")
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var status int
+	var response map[string]interface{}
+	for _, code := range codes {
+		if n, err := strconv.Atoi(code); err == nil && n >= 200 && n < 400 {
+			status = n
+			response = g.resolveMap(responses[code])
+			break
+		}
+	}
+	if status == 0 {
+		return
+	}
+	req.Status = status
+
+	content := mapField(response, "content")
+	if len(content) == 0 {
+		return
+	}
+
+	mediaType, media := pickMediaType(content)
+	req.Asserts = append(req.Asserts, fmt.Sprintf("header \"Content-Type\" contains %s", quoteHurlString(mediaType)))
+
+	if !strings.Contains(mediaType, "json") {
+		return
+	}
+
+	schema := g.resolveMap(media["schema"])
+	switch stringField(schema, "type") {
+	case "array":
+		req.Asserts = append(req.Asserts, `jsonpath "$" isCollection`)
+	case "object", "":
+		required := listField(schema, "required")
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				req.Asserts = append(req.Asserts, fmt.Sprintf("jsonpath %s exists", quoteHurlString("$."+name)))
+			}
+		}
+	}
+}
+
+// addBaseURL adds the base URL variable to every environment that does not define it yet.
+// Servers whose description matches an environment name are used for that environment.
+func (g *openAPIGenerator) addBaseURL(a *App) error {
+	servers := listField(g.doc, "servers")
+	defaultURL := "http://localhost"
+	serverByEnv := make(map[string]string)
+	for i, s := range servers {
+		server, _ := s.(map[string]interface{})
+		url := expandServerURL(server)
+		if url == "" {
+			continue
+		}
+		if i == 0 {
+			defaultURL = url
+		}
+		if desc := strings.ToLower(stringField(server, "description")); desc != "" {
+			serverByEnv[desc] = url
+		}
+	}
+	defaultURL = strings.TrimRight(defaultURL, "/")
+
+	return a.updateEnvConfig(func(config *EnvConfig) error {
+		for name, vars := range config.Environments {
+			url := defaultURL
+			if match, ok := serverByEnv[strings.ToLower(name)]; ok {
+				url = strings.TrimRight(match, "/")
+			}
+			if secret, err := isSecret(name, g.baseVar); err != nil {
+				return err
+			} else if secret {
+				g.result.warnf("%s is a secret of %s, it was left unchanged", g.baseVar, name)
+				continue
+			}
+			if existing, ok := vars[g.baseVar]; ok {
+				if existing != url {
+					g.result.warnf("environment %s already defines %s, it was left unchanged", name, g.baseVar)
+				}
+				continue
+			}
+			if vars == nil {
+				vars = make(map[string]string)
+				config.Environments[name] = vars
+			}
+			vars[g.baseVar] = url
+			g.result.Environments = append(g.result.Environments, name)
+		}
+		sort.Strings(g.result.Environments)
+		return nil
+	})
+}
+
+// expandServerURL substitutes server variables with their default values
+func expandServerURL(server map[string]interface{}) string {
+	url := stringField(server, "url")
+	for name, v := range mapField(server, "variables") {
+		variable, _ := v.(map[string]interface{})
+		url = strings.ReplaceAll(url, "{"+name+"}", exampleString(variable["default"]))
+	}
+	return url
+}
+
+// pickMediaType prefers JSON content and otherwise takes the first media type in sorted order
+func pickMediaType(content map[string]interface{}) (string, map[string]interface{}) {
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	chosen := types[0]
+	for _, t := range types {
+		if strings.Contains(t, "json") {
+			chosen = t
+			break
+		}
+	}
+	media, _ := content[chosen].(map[string]interface{})
+	return chosen, media
+}
+
+// mediaExample returns the documented example of a media type or builds one from its schema
+func (g *openAPIGenerator) mediaExample(media map[string]interface{}) interface{} {
+	if example, ok := media["example"]; ok {
+		return example
+	}
+	for _, ex := range mapField(media, "examples") {
+		if value, ok := g.resolveMap(ex)["value"]; ok {
+			return value
+		}
+	}
+	return g.schemaExample(media["schema"], 0)
+}
+
+// schemaExample builds an example value from a schema
+func (g *openAPIGenerator) schemaExample(s interface{}, depth int) interface{} {
+	schema := g.resolveMap(s)
+	if schema == nil || depth > openAPIMaxDepth {
+		return nil
+	}
+
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	if def, ok := schema["default"]; ok {
+		return def
+	}
+	if enum := listField(schema, "enum"); len(enum) > 0 {
+		return enum[0]
+	}
+
+	if all := listField(schema, "allOf"); len(all) > 0 {
+		merged := make(map[string]interface{})
+		for _, sub := range all {
+			if obj, ok := g.schemaExample(sub, depth+1).(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options := listField(schema, key); len(options) > 0 {
+			return g.schemaExample(options[0], depth+1)
+		}
+	}
+
+	schemaType := stringField(schema, "type")
+	if schemaType == "" {
+		// OpenAPI 3.1 allows a list of types
+		if types := listField(schema, "type"); len(types) > 0 {
+			schemaType, _ = types[0].(string)
+		} else if schema["properties"] != nil {
+			schemaType = "object"
+		}
+	}
+
+	switch schemaType {
+	case "object":
+		obj := make(map[string]interface{})
+		for name, prop := range mapField(schema, "properties") {
+			obj[name] = g.schemaExample(prop, depth+1)
+		}
+		return obj
+	case "array":
+		item := g.schemaExample(schema["items"], depth+1)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return false
+	case "string":
+		return stringExample(stringField(schema, "format"))
+	}
+
+	return nil
+}
+
+// stringExample returns an example string for a string format
+func stringExample(format string) string {
+	switch format {
+	case "date":
+		return "2024-01-01"
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	case "binary", "byte":
+		return ""
+	}
+	return "string"
+}
+
+// resolve follows local $ref pointers such as #/components/schemas/User
+func (g *openAPIGenerator) resolve(v interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return v
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			g.result.warnf("external reference %s is not supported", ref)
+			return nil
+		}
+
+		var target interface{} = g.doc
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+			m, _ := target.(map[string]interface{})
+			target = m[part]
+		}
+		v = target
+	}
+	return nil
+}
+
+// resolveMap resolves v and returns it as an object, or nil
+func (g *openAPIGenerator) resolveMap(v interface{}) map[string]interface{} {
+	m, _ := g.resolve(v).(map[string]interface{})
+	return m
+}
+
+// exampleFields flattens an example object into key/value pairs
+func exampleFields(example interface{}) []hurlKeyValue {
+	obj, _ := example.(map[string]interface{})
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]hurlKeyValue, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, hurlKeyValue{Key: k, Value: exampleString(obj[k])})
+	}
+	return fields
+}
+
+// exampleString converts an example value to text, encoding structured values as JSON
+func exampleString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(value)
+		return string(data)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// normalizeYAML converts the map[interface{}]interface{} values produced for
+// non string keys (like response codes) to map[string]interface{}
+func normalizeYAML(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = normalizeYAML(item)
+		}
+		return value
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalizeYAML(item)
+		}
+		return value
+	}
+	return v
+}
+
+// mapField returns obj[key] as an object, or nil
+func mapField(obj map[string]interface{}, key string) map[string]interface{} {
+	m, _ := obj[key].(map[string]interface{})
+	return m
+}
+
+// listField returns obj[key] as a list, or nil
+func listField(obj map[string]interface{}, key string) []interface{} {
+	l, _ := obj[key].([]interface{})
+	return l
+}
+
+// stringField returns obj[key] as a string, or ""
+func stringField(obj map[string]interface{}, key string) string {
+	s, _ := obj[key].(string)
+	return s
+}
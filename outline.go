@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EntryOutline summarizes one entry of a hurl file for the outline sidebar.
+// Status is one of passed, failed or notRun, from the latest stored report.
+type EntryOutline struct {
+	Index       int    `json:"index"`
+	Name        string `json:"name"`
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	ResolvedURL string `json:"resolvedUrl"`
+	StartLine   int    `json:"startLine"`
+	EndLine     int    `json:"endLine"`
+	Asserts     int    `json:"asserts"`
+	Captures    int    `json:"captures"`
+	Status      string `json:"status"`
+	Time        int    `json:"time"`
+}
+
+// hurlReport is the subset of hurl's JSON report used by the outline
+type hurlReport []struct {
+	Entries []hurlReportEntry `json:"entries"`
+}
+
+type hurlReportEntry struct {
+	Index   int `json:"index"`
+	Time    int `json:"time"`
+	Asserts []struct {
+		Success bool `json:"success"`
+	} `json:"asserts"`
+}
+
+// GetFileOutline lists the entries of a hurl file with the result of each
+// entry in the latest run. Entries missing from the report were not run.
+func (a *App) GetFileOutline(filePath string) ([]EntryOutline, error) {
+	content, err := a.GetFileContent(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := a.latestEntryResults(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without an environment the URLs are shown with their templates
+	vars, err := a.activeVariables()
+	if err != nil {
+		vars = map[string]string{}
+	}
+
+	return entryOutlines(parseHurl(content), results, vars), nil
+}
+
+// entryOutlines combines the entries of a parsed file with report results keyed by entry index
+func entryOutlines(file *HurlFile, results map[int]hurlReportEntry, vars map[string]string) []EntryOutline {
+	outlines := []EntryOutline{}
+	for _, entry := range file.Entries {
+		outline := EntryOutline{
+			Index:       entry.Index,
+			Method:      entry.Request.Method,
+			URL:         entry.Request.URL,
+			ResolvedURL: resolveTemplates(entry.Request.URL, vars),
+			StartLine:   entry.Span.Start.Line,
+			EndLine:     entry.Span.End.Line,
+			Status:      "notRun",
+		}
+
+		// The comment right above the request line names the entry
+		if n := len(entry.Comments); n > 0 {
+			outline.Name = entry.Comments[n-1].Text
+		}
+
+		if entry.Response != nil {
+			for _, section := range entry.Response.Sections {
+				outline.Asserts += len(section.Asserts)
+				outline.Captures += len(section.Captures)
+			}
+		}
+
+		if result, ok := results[entry.Index]; ok {
+			outline.Status = "passed"
+			outline.Time = result.Time
+			for _, assert := range result.Asserts {
+				if !assert.Success {
+					outline.Status = "failed"
+					break
+				}
+			}
+		}
+
+		outlines = append(outlines, outline)
+	}
+	return outlines
+}
+
+// latestEntryResults reads the stored report of a hurl file, keyed by entry index
+func (a *App) latestEntryResults(filePath string) (map[int]hurlReportEntry, error) {
+	results := make(map[int]hurlReportEntry)
+
+	content, err := a.GetExistingReport(filePath)
+	if err != nil || content == "" {
+		return results, err
+	}
+
+	var report hurlReport
+	if err := json.Unmarshal([]byte(content), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+	for _, file := range report {
+		for _, entry := range file.Entries {
+			results[entry.Index] = entry
+		}
+	}
+	return results, nil
+}
+
+// resolveTemplates replaces {{name}} templates with their value, leaving
+// unknown variables and function calls as written
+func resolveTemplates(text string, vars map[string]string) string {
+	return templateRegex.ReplaceAllStringFunc(text, func(template string) string {
+		name := templateRegex.FindStringSubmatch(template)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return template
+	})
+}
@@ -0,0 +1,745 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// postmanCollection is the subset of a Postman Collection v2.1 document used by the importer
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+	Auth     *postmanAuth      `json:"auth,omitempty"`
+}
+
+type postmanInfo struct {
+	PostmanID   string          `json:"_postman_id,omitempty"`
+	Name        string          `json:"name"`
+	Description json.RawMessage `json:"description,omitempty"`
+	Schema      string          `json:"schema"`
+}
+
+// postmanItem is either a folder (Item is set) or a request (Request is set)
+type postmanItem struct {
+	Name        string          `json:"name"`
+	Description json.RawMessage `json:"description,omitempty"`
+	Item        []postmanItem   `json:"item,omitempty"`
+	Request     *postmanRequest `json:"request,omitempty"`
+	Event       []postmanEvent  `json:"event,omitempty"`
+	Auth        *postmanAuth    `json:"auth,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string          `json:"method"`
+	URL         postmanURL      `json:"url"`
+	Header      []postmanHeader `json:"header,omitempty"`
+	Body        *postmanBody    `json:"body,omitempty"`
+	Auth        *postmanAuth    `json:"auth,omitempty"`
+	Description json.RawMessage `json:"description,omitempty"`
+}
+
+// UnmarshalJSON accepts both the object form and the plain URL string form of a request
+func (r *postmanRequest) UnmarshalJSON(data []byte) error {
+	var url string
+	if err := json.Unmarshal(data, &url); err == nil {
+		*r = postmanRequest{Method: "GET", URL: postmanURL{Raw: url}}
+		return nil
+	}
+
+	type plain postmanRequest
+	var req plain
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+	*r = postmanRequest(req)
+	return nil
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Query    []postmanQuery    `json:"query,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+// UnmarshalJSON accepts both the object form and the plain string form of a URL
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*u = postmanURL{Raw: raw}
+		return nil
+	}
+
+	type plain postmanURL
+	var url plain
+	if err := json.Unmarshal(data, &url); err != nil {
+		return err
+	}
+	*u = postmanURL(url)
+	return nil
+}
+
+type postmanQuery struct {
+	Key      string  `json:"key"`
+	Value    *string `json:"value"`
+	Disabled bool    `json:"disabled,omitempty"`
+}
+
+type postmanHeader struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type postmanVariable struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Type     string      `json:"type,omitempty"`
+	Disabled bool        `json:"disabled,omitempty"`
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw,omitempty"`
+	URLEncoded []postmanFormPart `json:"urlencoded,omitempty"`
+	FormData   []postmanFormPart `json:"formdata,omitempty"`
+	File       *postmanFile      `json:"file,omitempty"`
+	GraphQL    *postmanGraphQL   `json:"graphql,omitempty"`
+	Options    json.RawMessage   `json:"options,omitempty"`
+}
+
+type postmanFormPart struct {
+	Key         string      `json:"key"`
+	Value       string      `json:"value,omitempty"`
+	Type        string      `json:"type,omitempty"`
+	Src         interface{} `json:"src,omitempty"`
+	ContentType string      `json:"contentType,omitempty"`
+	Disabled    bool        `json:"disabled,omitempty"`
+}
+
+type postmanFile struct {
+	Src string `json:"src"`
+}
+
+type postmanGraphQL struct {
+	Query     string `json:"query"`
+	Variables string `json:"variables,omitempty"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanVariable `json:"bearer,omitempty"`
+	Basic  []postmanVariable `json:"basic,omitempty"`
+	APIKey []postmanVariable `json:"apikey,omitempty"`
+}
+
+// attr returns the value of an auth attribute such as "token" or "username"
+func (a *postmanAuth) attr(attrs []postmanVariable, key string) string {
+	for _, v := range attrs {
+		if v.Key == key {
+			return postmanValueString(v.Value)
+		}
+	}
+	return ""
+}
+
+type postmanEvent struct {
+	Listen string        `json:"listen"`
+	Script postmanScript `json:"script"`
+}
+
+type postmanScript struct {
+	Type string   `json:"type,omitempty"`
+	Exec []string `json:"exec"`
+}
+
+// UnmarshalJSON accepts exec as either an array of lines or a single string
+func (s *postmanScript) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Exec json.RawMessage `json:"exec"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw.Exec) == 0 {
+		return nil
+	}
+
+	var lines []string
+	if err := json.Unmarshal(raw.Exec, &lines); err == nil {
+		s.Exec = lines
+		return nil
+	}
+
+	var script string
+	if err := json.Unmarshal(raw.Exec, &script); err != nil {
+		return err
+	}
+	s.Exec = strings.Split(script, "\n")
+	return nil
+}
+
+// postmanEnvironment is a Postman environment export
+type postmanEnvironment struct {
+	ID     string                    `json:"id,omitempty"`
+	Name   string                    `json:"name"`
+	Values []postmanEnvironmentValue `json:"values"`
+	Scope  string                    `json:"_postman_variable_scope,omitempty"`
+}
+
+type postmanEnvironmentValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+// postmanDynamicVariables maps Postman's built-in dynamic variables to hurl's generators
+var postmanDynamicVariables = map[string]string{
+	"$guid":         "newUuid",
+	"$randomUUID":   "newUuid",
+	"$isoTimestamp": "newDate",
+}
+
+// postmanValueString converts a Postman variable value to its string form
+func postmanValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// postmanDescription extracts the text of a description, which can be a string or an object
+func postmanDescription(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var obj struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return obj.Content
+	}
+	return ""
+}
+
+// postmanImporter holds the state of a single collection import
+type postmanImporter struct {
+	app    *App
+	result *ImportResult
+}
+
+// mapVariable converts a Postman variable name to a hurl variable name
+func (p *postmanImporter) mapVariable(name string) string {
+	if mapped, ok := postmanDynamicVariables[name]; ok {
+		return mapped
+	}
+	if strings.HasPrefix(name, "$") {
+		p.result.warnf("dynamic variable {{%s}} has no hurl equivalent", name)
+	}
+	return sanitizeVariableName(name)
+}
+
+// convert rewrites the {{var}} usages in text
+func (p *postmanImporter) convert(text string) string {
+	return convertTemplates(text, p.mapVariable)
+}
+
+// ImportPostmanCollection imports a Postman Collection v2.1 JSON file into the
+// current directory. Folders become directories and every request becomes a
+// .hurl file. Collection variables are added to the global variables.
+func (a *App) ImportPostmanCollection(collectionPath string) (*ImportResult, error) {
+	data, err := os.ReadFile(collectionPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection %s: %w", collectionPath, err)
+	}
+
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("invalid Postman collection: %w", err)
+	}
+	if !strings.Contains(collection.Info.Schema, "v2.1") && !strings.Contains(collection.Info.Schema, "v2.0") {
+		return nil, fmt.Errorf("unsupported Postman collection schema %q, expected v2.1", collection.Info.Schema)
+	}
+
+	if a.currentDir == "" {
+		return nil, fmt.Errorf("no current directory set")
+	}
+
+	result := &ImportResult{}
+	importer := &postmanImporter{app: a, result: result}
+
+	rootPath := uniquePath(a.currentDir, sanitizeFileName(collection.Info.Name))
+	if err := importer.importItems(collection.Item, rootPath, collection.Auth); err != nil {
+		return result, err
+	}
+
+	if len(collection.Variable) > 0 {
+		if err := importer.mergeCollectionVariables(collection.Variable); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// importItems writes the items of a folder into dirPath, recursing into sub folders
+func (p *postmanImporter) importItems(items []postmanItem, dirPath string, auth *postmanAuth) error {
+	if err := p.app.createImportDir(dirPath, p.result); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		itemAuth := auth
+		if item.Auth != nil {
+			itemAuth = item.Auth
+		}
+
+		if item.Request == nil {
+			subDir := uniquePath(dirPath, sanitizeFileName(item.Name))
+			if err := p.importItems(item.Item, subDir, itemAuth); err != nil {
+				return err
+			}
+			continue
+		}
+
+		request := p.convertRequest(item, itemAuth)
+		filePath := uniquePath(dirPath, sanitizeFileName(item.Name)+".hurl")
+		if err := writeNewFile(filePath, request.render()); err != nil {
+			return err
+		}
+		p.result.Created = append(p.result.Created, filePath)
+	}
+
+	return nil
+}
+
+// convertRequest converts a single Postman request item to a hurl request
+func (p *postmanImporter) convertRequest(item postmanItem, auth *postmanAuth) *hurlRequest {
+	src := item.Request
+	if src.Auth != nil {
+		auth = src.Auth
+	}
+
+	req := &hurlRequest{
+		Method: strings.ToUpper(src.Method),
+	}
+	if req.Method == "" {
+		req.Method = "GET"
+	}
+
+	if item.Name != "" {
+		req.Comments = append(req.Comments, item.Name)
+	}
+	if desc := postmanDescription(src.Description); desc != "" {
+		req.Comments = append(req.Comments, desc)
+	}
+
+	// Strip the query string from the raw URL when it is also given as structured params
+	rawURL := src.URL.Raw
+	if len(src.URL.Query) > 0 {
+		if i := strings.Index(rawURL, "?"); i >= 0 {
+			rawURL = rawURL[:i]
+		}
+		for _, q := range src.URL.Query {
+			if q.Disabled {
+				continue
+			}
+			value := ""
+			if q.Value != nil {
+				value = *q.Value
+			}
+			req.Query = append(req.Query, hurlKeyValue{Key: p.convert(q.Key), Value: p.convert(value)})
+		}
+	}
+
+	// Path variables (:id) become hurl variables
+	for _, v := range src.URL.Variable {
+		pathVar := regexp.MustCompile(`:` + regexp.QuoteMeta(v.Key) + `(/|\?|$)`)
+		rawURL = pathVar.ReplaceAllString(rawURL, "{{"+sanitizeVariableName(v.Key)+"}}$1")
+	}
+	req.URL = p.convert(rawURL)
+
+	for _, h := range src.Header {
+		if h.Disabled {
+			continue
+		}
+		req.Headers = append(req.Headers, hurlKeyValue{Key: p.convert(h.Key), Value: p.convert(h.Value)})
+	}
+
+	p.convertAuth(auth, req, item.Name)
+	p.convertBody(src.Body, req, item.Name)
+
+	for _, event := range item.Event {
+		switch event.Listen {
+		case "test":
+			untranslated := translatePostmanTests(event.Script.Exec, req, p.mapVariable)
+			if untranslated > 0 {
+				p.result.warnf("%s: %d test script line(s) could not be translated to asserts", item.Name, untranslated)
+			}
+		case "prerequest":
+			if len(strings.TrimSpace(strings.Join(event.Script.Exec, ""))) > 0 {
+				p.result.warnf("%s: pre-request script was not imported", item.Name)
+			}
+		}
+	}
+
+	return req
+}
+
+// convertAuth adds the request's authentication as headers or a [BasicAuth] section
+func (p *postmanImporter) convertAuth(auth *postmanAuth, req *hurlRequest, name string) {
+	if auth == nil {
+		return
+	}
+
+	switch auth.Type {
+	case "", "noauth":
+	case "bearer":
+		token := p.convert(auth.attr(auth.Bearer, "token"))
+		req.Headers = append(req.Headers, hurlKeyValue{Key: "Authorization", Value: "Bearer " + token})
+	case "basic":
+		req.BasicAuth = &hurlKeyValue{
+			Key:   p.convert(auth.attr(auth.Basic, "username")),
+			Value: p.convert(auth.attr(auth.Basic, "password")),
+		}
+	case "apikey":
+		key := p.convert(auth.attr(auth.APIKey, "key"))
+		value := p.convert(auth.attr(auth.APIKey, "value"))
+		if auth.attr(auth.APIKey, "in") == "query" {
+			req.Query = append(req.Query, hurlKeyValue{Key: key, Value: value})
+		} else {
+			req.Headers = append(req.Headers, hurlKeyValue{Key: key, Value: value})
+		}
+	default:
+		p.result.warnf("%s: %s authentication is not supported", name, auth.Type)
+	}
+}
+
+// convertBody sets the request body or form sections
+func (p *postmanImporter) convertBody(body *postmanBody, req *hurlRequest, name string) {
+	if body == nil {
+		return
+	}
+
+	switch body.Mode {
+	case "raw":
+		req.Body = p.convert(body.Raw)
+	case "urlencoded":
+		for _, part := range body.URLEncoded {
+			if part.Disabled {
+				continue
+			}
+			req.Form = append(req.Form, hurlKeyValue{Key: p.convert(part.Key), Value: p.convert(part.Value)})
+		}
+	case "formdata":
+		for _, part := range body.FormData {
+			if part.Disabled {
+				continue
+			}
+			if part.Type == "file" {
+				src := postmanValueString(part.Src)
+				value := fmt.Sprintf("file,%s;", escapeHurlValue(src))
+				if part.ContentType != "" {
+					value += " " + part.ContentType
+				}
+				req.Multipart = append(req.Multipart, hurlKeyValue{Key: p.convert(part.Key), Value: value})
+				continue
+			}
+			req.Multipart = append(req.Multipart, hurlKeyValue{Key: p.convert(part.Key), Value: escapeHurlValue(p.convert(part.Value))})
+		}
+	case "file":
+		if body.File != nil && body.File.Src != "" {
+			req.Body = fmt.Sprintf("file,%s;", escapeHurlValue(body.File.Src))
+		}
+	case "graphql":
+		if body.GraphQL == nil {
+			return
+		}
+		payload := map[string]interface{}{"query": body.GraphQL.Query}
+		if vars := strings.TrimSpace(body.GraphQL.Variables); vars != "" {
+			payload["variables"] = json.RawMessage(vars)
+		}
+		data, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			p.result.warnf("%s: failed to convert GraphQL body: %v", name, err)
+			return
+		}
+		req.Body = p.convert(string(data))
+	default:
+		p.result.warnf("%s: body mode %q is not supported", name, body.Mode)
+	}
+}
+
+// mergeCollectionVariables adds collection variables to the global variables,
+// keeping existing global values
+func (p *postmanImporter) mergeCollectionVariables(variables []postmanVariable) error {
+	return p.app.updateEnvConfig(func(config *EnvConfig) error {
+		for _, v := range variables {
+			if v.Disabled {
+				continue
+			}
+			key := sanitizeVariableName(v.Key)
+			if secret, err := isSecret("", key); err != nil {
+				return err
+			} else if secret {
+				p.result.warnf("global variable %s is a secret, collection value was not imported", key)
+				continue
+			}
+			value := p.convert(postmanValueString(v.Value))
+			if existing, ok := config.Global[key]; ok && existing != value {
+				p.result.warnf("global variable %s already exists, collection value was not imported", key)
+				continue
+			}
+			config.Global[key] = value
+		}
+		return nil
+	})
+}
+
+// ImportPostmanEnvironment merges a Postman environment export into the
+// environment of the same name in env.json, creating it if needed. Names that
+// are not valid environment names are sanitized like variable names.
+func (a *App) ImportPostmanEnvironment(environmentPath string) (*ImportResult, error) {
+	data, err := os.ReadFile(environmentPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment %s: %w", environmentPath, err)
+	}
+
+	var env postmanEnvironment
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("invalid Postman environment: %w", err)
+	}
+	if strings.TrimSpace(env.Name) == "" {
+		return nil, fmt.Errorf("postman environment has no name")
+	}
+
+	result := &ImportResult{}
+	importer := &postmanImporter{app: a, result: result}
+
+	name := sanitizeVariableName(env.Name)
+	if name != strings.TrimSpace(env.Name) {
+		result.warnf("environment %s was renamed to %s", env.Name, name)
+	}
+	err = a.updateEnvConfig(func(config *EnvConfig) error {
+		vars, ok := config.Environments[name]
+		if !ok {
+			vars = make(map[string]string)
+			config.Environments[name] = vars
+		}
+
+		for _, v := range env.Values {
+			if !v.Enabled {
+				continue
+			}
+			key := sanitizeVariableName(v.Key)
+			if key != v.Key {
+				result.warnf("variable %s was renamed to %s", v.Key, key)
+			}
+			if secret, err := isSecret(name, key); err != nil {
+				return err
+			} else if secret {
+				result.warnf("%s is a secret of %s, its value was not imported", key, name)
+				continue
+			}
+			vars[key] = importer.convert(v.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Environments = append(result.Environments, name)
+	return result, nil
+}
+
+var (
+	postmanJSONVarRegex   = regexp.MustCompile(`(?:var|let|const)\s+(\w+)\s*=\s*pm\.response\.json\(\)`)
+	postmanStatusRegex    = regexp.MustCompile(`pm\.response\.to\.have\.status\(\s*(\d{3})\s*\)`)
+	postmanCodeRegex      = regexp.MustCompile(`pm\.expect\(\s*pm\.response\.code\s*\)\.to\.(?:eql|equal|eq|be\.equal)\(\s*(\d{3})\s*\)`)
+	postmanHeaderRegex    = regexp.MustCompile(`pm\.response\.to\.have\.header\(\s*(['"])(.*?)['"]\s*(?:,\s*(['"])(.*?)['"]\s*)?\)`)
+	postmanDurationRegex  = regexp.MustCompile(`pm\.expect\(\s*pm\.response\.responseTime\s*\)\.to\.be\.(below|above|lessThan|greaterThan)\(\s*(\d+)\s*\)`)
+	postmanBodyRegex      = regexp.MustCompile(`pm\.expect\(\s*pm\.response\.text\(\)\s*\)\.to\.(?:include|contain|have\.string)\(\s*(['"])(.*?)['"]\s*\)`)
+	postmanExpectRegex    = regexp.MustCompile(`pm\.expect\(\s*(pm\.response\.json\(\)|[A-Za-z_$][\w$]*)((?:\.[A-Za-z_$][\w$]*|\[\d+\]|\[['"][^'"]+['"]\])*)\s*\)\.to\.(.+?)\s*;?\s*$`)
+	postmanSetHeaderRegex = regexp.MustCompile(`pm\.(?:environment|collectionVariables|globals|variables)\.set\(\s*['"](.+?)['"]\s*,\s*pm\.response\.headers\.get\(\s*['"](.+?)['"]\s*\)\s*\)`)
+	postmanSetRegex       = regexp.MustCompile(`pm\.(?:environment|collectionVariables|globals|variables)\.set\(\s*['"](.+?)['"]\s*,\s*(pm\.response\.json\(\)|[A-Za-z_$][\w$]*)((?:\.[A-Za-z_$][\w$]*|\[\d+\]|\[['"][^'"]+['"]\])*)\s*\)`)
+)
+
+// translatePostmanTests converts the common pm.* assertions in a test script
+// to hurl asserts and captures. It returns the number of statements it could
+// not translate.
+func translatePostmanTests(lines []string, req *hurlRequest, mapVariable func(string) string) int {
+	jsonVars := map[string]bool{"pm.response.json()": true}
+	for _, line := range lines {
+		if m := postmanJSONVarRegex.FindStringSubmatch(line); m != nil {
+			jsonVars[m[1]] = true
+		}
+	}
+
+	untranslated := 0
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") || postmanJSONVarRegex.MatchString(trimmed) {
+			continue
+		}
+		// Test wrappers and closing braces carry no assertion on their own
+		if strings.HasPrefix(trimmed, "pm.test(") && !strings.Contains(trimmed, "pm.expect") && !strings.Contains(trimmed, "pm.response.to") {
+			continue
+		}
+		if strings.Trim(trimmed, "});") == "" {
+			continue
+		}
+
+		if m := postmanStatusRegex.FindStringSubmatch(trimmed); m != nil {
+			req.Status, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if m := postmanCodeRegex.FindStringSubmatch(trimmed); m != nil {
+			req.Status, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if m := postmanHeaderRegex.FindStringSubmatch(trimmed); m != nil {
+			if m[3] != "" {
+				req.Asserts = append(req.Asserts, fmt.Sprintf("header %s == %s", quoteHurlString(m[2]), quoteHurlString(m[4])))
+			} else {
+				req.Asserts = append(req.Asserts, fmt.Sprintf("header %s exists", quoteHurlString(m[2])))
+			}
+			continue
+		}
+		if m := postmanDurationRegex.FindStringSubmatch(trimmed); m != nil {
+			op := "<"
+			if m[1] == "above" || m[1] == "greaterThan" {
+				op = ">"
+			}
+			req.Asserts = append(req.Asserts, fmt.Sprintf("duration %s %s", op, m[2]))
+			continue
+		}
+		if m := postmanBodyRegex.FindStringSubmatch(trimmed); m != nil {
+			req.Asserts = append(req.Asserts, fmt.Sprintf("body contains %s", quoteHurlString(m[2])))
+			continue
+		}
+		if m := postmanSetHeaderRegex.FindStringSubmatch(trimmed); m != nil {
+			req.Captures = append(req.Captures, hurlKeyValue{
+				Key:   mapVariable(m[1]),
+				Value: "header " + quoteHurlString(m[2]),
+			})
+			continue
+		}
+		if m := postmanSetRegex.FindStringSubmatch(trimmed); m != nil && jsonVars[m[2]] {
+			req.Captures = append(req.Captures, hurlKeyValue{
+				Key:   mapVariable(m[1]),
+				Value: "jsonpath " + quoteHurlString(jsPathToJSONPath(m[3])),
+			})
+			continue
+		}
+		if m := postmanExpectRegex.FindStringSubmatch(trimmed); m != nil && jsonVars[m[1]] {
+			if predicate, ok := chaiToHurlPredicate(m[3]); ok {
+				req.Asserts = append(req.Asserts, fmt.Sprintf("jsonpath %s %s", quoteHurlString(jsPathToJSONPath(m[2])), predicate))
+				continue
+			}
+		}
+
+		untranslated++
+	}
+
+	return untranslated
+}
+
+// jsPathToJSONPath converts a JavaScript property path such as .data[0].id to $.data[0].id
+func jsPathToJSONPath(path string) string {
+	path = strings.ReplaceAll(path, "['", "[\"")
+	path = strings.ReplaceAll(path, "']", "\"]")
+	return "$" + path
+}
+
+var (
+	chaiCompareRegex = regexp.MustCompile(`^(?:be\.)?(eql|equal|eq|equals|include|contain|contains|above|below|greaterThan|lessThan)\((.+)\)$`)
+	chaiTypeRegex    = regexp.MustCompile(`^be\.an?\(\s*['"](\w+)['"]\s*\)$`)
+)
+
+// chaiToHurlPredicate converts a chai assertion chain (the part after .to.) to a hurl predicate
+func chaiToHurlPredicate(chain string) (string, bool) {
+	chain = strings.TrimSuffix(strings.TrimSpace(chain), ";")
+
+	switch chain {
+	case "exist", "not.be.undefined", "be.ok":
+		return "exists", true
+	case "not.exist", "be.undefined":
+		return "not exists", true
+	case "be.true":
+		return "== true", true
+	case "be.false":
+		return "== false", true
+	case "be.null":
+		return "== null", true
+	case "be.empty":
+		return "isEmpty", true
+	}
+
+	if m := chaiTypeRegex.FindStringSubmatch(chain); m != nil {
+		types := map[string]string{
+			"string":  "isString",
+			"number":  "isNumber",
+			"boolean": "isBoolean",
+			"array":   "isCollection",
+			"object":  "isCollection",
+		}
+		if predicate, ok := types[m[1]]; ok {
+			return predicate, true
+		}
+		return "", false
+	}
+
+	m := chaiCompareRegex.FindStringSubmatch(chain)
+	if m == nil {
+		return "", false
+	}
+	value, ok := jsLiteralToHurl(m[2])
+	if !ok {
+		return "", false
+	}
+
+	ops := map[string]string{
+		"eql": "==", "equal": "==", "eq": "==", "equals": "==",
+		"include": "contains", "contain": "contains", "contains": "contains",
+		"above": ">", "greaterThan": ">",
+		"below": "<", "lessThan": "<",
+	}
+	return ops[m[1]] + " " + value, true
+}
+
+// jsLiteralToHurl converts a JavaScript literal (string, number, boolean, null) to hurl syntax
+func jsLiteralToHurl(literal string) (string, bool) {
+	literal = strings.TrimSpace(literal)
+	switch literal {
+	case "true", "false", "null":
+		return literal, true
+	}
+	if _, err := strconv.ParseFloat(literal, 64); err == nil {
+		return literal, true
+	}
+	if len(literal) >= 2 {
+		quote := literal[0]
+		if (quote == '"' || quote == '\'') && literal[len(literal)-1] == quote {
+			return quoteHurlString(literal[1 : len(literal)-1]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestImportPostmanEnvironment(t *testing.T) {
+	tests := []struct {
+		name     string
+		envName  string
+		want     string
+		warnings []string
+		err      string
+	}{
+		{name: "valid name", envName: "staging", want: "staging"},
+		{name: "name with spaces", envName: "My Env", want: "My_Env", warnings: []string{"environment My Env was renamed to My_Env"}},
+		{name: "name with a slash", envName: "prod/eu", want: "prod_eu", warnings: []string{"environment prod/eu was renamed to prod_eu"}},
+		{name: "blank name", envName: "  ", err: "postman environment has no name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			a := NewApp()
+
+			data, err := json.Marshal(postmanEnvironment{
+				Name:   tt.envName,
+				Values: []postmanEnvironmentValue{{Key: "api_url", Value: "https://example.com", Enabled: true}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "env.postman_environment.json")
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := a.ImportPostmanEnvironment(path)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(result.Environments, []string{tt.want}) || !slices.Equal(result.Warnings, tt.warnings) {
+				t.Errorf("result = %+v, want environment %s and warnings %q", result, tt.want, tt.warnings)
+			}
+
+			config, err := a.loadEnvConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := config.Environments[tt.want]["api_url"]; got != "https://example.com" {
+				t.Errorf("%s api_url = %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// postmanCollectionSchema is the schema URL written to exported collections
+const postmanCollectionSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// ExportResult summarises the files written by an exporter
+type ExportResult struct {
+	CollectionPath  string            `json:"collectionPath"`
+	EnvironmentPath string            `json:"environmentPath,omitempty"`
+	Requests        int               `json:"requests"`
+	Issues          []ConversionIssue `json:"issues"`
+}
+
+// hurlGeneratorsToPostman maps hurl's generators to Postman dynamic variables
+var hurlGeneratorsToPostman = map[string]string{
+	"newUuid": "$guid",
+	"newDate": "$isoTimestamp",
+}
+
+// ExportPostmanCollection walks dirPath and writes its .hurl files as a Postman
+// Collection v2.1 into outputDir, with folders mirroring the directory tree.
+// When environment is not empty its flattened variables are also written as a
+// Postman environment file.
+func (a *App) ExportPostmanCollection(dirPath string, outputDir string, environment string) (*ExportResult, error) {
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info for %s: %w", dirPath, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path %s is not a directory", dirPath)
+	}
+
+	result := &ExportResult{}
+	name := filepath.Base(dirPath)
+
+	items, err := a.exportPostmanItems(dirPath, dirPath, result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Requests == 0 {
+		return nil, fmt.Errorf("no hurl requests found in %s", dirPath)
+	}
+
+	collection := postmanCollection{
+		Info: postmanInfo{
+			PostmanID: newUUID(),
+			Name:      name,
+			Schema:    postmanCollectionSchema,
+		},
+		Item: items,
+	}
+
+	collectionPath := uniquePath(outputDir, sanitizeFileName(name)+".postman_collection.json")
+	if err := writeJSONFile(collectionPath, collection); err != nil {
+		return nil, err
+	}
+	result.CollectionPath = collectionPath
+
+	if environment != "" {
+		vars, err := a.GetFlattenedVariables(environment)
+		if err != nil {
+			return result, err
+		}
+
+		env := postmanEnvironment{
+			ID:    newUUID(),
+			Name:  environment,
+			Scope: "environment",
+		}
+		for _, key := range sortedKeys(vars) {
+			env.Values = append(env.Values, postmanEnvironmentValue{
+				Key:     key,
+				Value:   vars[key],
+				Type:    "default",
+				Enabled: true,
+			})
+		}
+
+		environmentPath := uniquePath(outputDir, sanitizeFileName(environment)+".postman_environment.json")
+		if err := writeJSONFile(environmentPath, env); err != nil {
+			return result, err
+		}
+		result.EnvironmentPath = environmentPath
+	}
+
+	return result, nil
+}
+
+// exportPostmanItems converts the hurl files and sub directories of dir to collection items
+func (a *App) exportPostmanItems(root string, dir string, result *ExportResult) ([]postmanItem, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	items := []postmanItem{}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+
+		if entry.IsDir() {
+			children, err := a.exportPostmanItems(root, path, result)
+			if err != nil {
+				return nil, err
+			}
+			if len(children) > 0 {
+				items = append(items, postmanItem{Name: entry.Name(), Item: children})
+			}
+			continue
+		}
+
+		if strings.ToLower(filepath.Ext(entry.Name())) != ".hurl" {
+			continue
+		}
+
+		content, err := a.GetFileContent(path)
+		if err != nil {
+			return nil, err
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		conversion := &ConversionResult{}
+		scanned := scanHurlRequests(content, conversion)
+		for _, issue := range conversion.Issues {
+			issue.Message = rel + ": " + issue.Message
+			result.Issues = append(result.Issues, issue)
+		}
+
+		baseName := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		requests := make([]postmanItem, 0, len(scanned))
+		for i, s := range scanned {
+			itemName := baseName
+			if len(scanned) > 1 {
+				itemName = fmt.Sprintf("%s #%d", baseName, i+1)
+			}
+			if len(s.Comments) > 0 && s.Comments[0] != "" {
+				itemName = s.Comments[0]
+			}
+			requests = append(requests, hurlEntryToPostman(s, itemName, rel, result))
+		}
+		result.Requests += len(requests)
+
+		// A file with several entries becomes a folder to keep its requests together
+		if len(requests) == 1 {
+			items = append(items, requests[0])
+		} else if len(requests) > 1 {
+			items = append(items, postmanItem{Name: baseName, Item: requests})
+		}
+	}
+
+	return items, nil
+}
+
+// hurlEntryToPostman converts a scanned hurl entry to a Postman request item
+func hurlEntryToPostman(entry *hurlScanEntry, name string, file string, result *ExportResult) postmanItem {
+	convert := func(text string) string {
+		return convertTemplates(text, func(v string) string {
+			if mapped, ok := hurlGeneratorsToPostman[v]; ok {
+				return mapped
+			}
+			return v
+		})
+	}
+
+	req := &postmanRequest{
+		Method: entry.Method,
+		URL:    postmanURL{Raw: convert(entry.URL)},
+	}
+	if len(entry.Comments) > 1 {
+		desc, _ := json.Marshal(strings.Join(entry.Comments[1:], "\n"))
+		req.Description = desc
+	}
+
+	if len(entry.Query) > 0 {
+		var params []string
+		for _, q := range entry.Query {
+			value := convert(q.Value)
+			req.URL.Query = append(req.URL.Query, postmanQuery{Key: q.Key, Value: &value})
+			params = append(params, httpQueryEscape(q.Key)+"="+httpQueryEscape(value))
+		}
+		separator := "?"
+		if strings.Contains(req.URL.Raw, "?") {
+			separator = "&"
+		}
+		req.URL.Raw += separator + strings.Join(params, "&")
+	}
+
+	for _, h := range entry.Headers {
+		req.Header = append(req.Header, postmanHeader{Key: h.Key, Value: convert(h.Value)})
+	}
+	if len(entry.Cookies) > 0 {
+		var cookies []string
+		for _, c := range entry.Cookies {
+			cookies = append(cookies, c.Key+"="+convert(c.Value))
+		}
+		req.Header = append(req.Header, postmanHeader{Key: "Cookie", Value: strings.Join(cookies, "; ")})
+	}
+
+	if entry.Auth != nil {
+		req.Auth = &postmanAuth{
+			Type: "basic",
+			Basic: []postmanVariable{
+				{Key: "username", Value: convert(entry.Auth.Key), Type: "string"},
+				{Key: "password", Value: convert(entry.Auth.Value), Type: "string"},
+			},
+		}
+	}
+
+	switch {
+	case len(entry.Form) > 0:
+		body := &postmanBody{Mode: "urlencoded"}
+		for _, f := range entry.Form {
+			body.URLEncoded = append(body.URLEncoded, postmanFormPart{Key: f.Key, Value: convert(f.Value), Type: "text"})
+		}
+		req.Body = body
+	case len(entry.Multipart) > 0:
+		body := &postmanBody{Mode: "formdata"}
+		for _, part := range entry.Multipart {
+			if strings.HasPrefix(part.Value, "file,") {
+				src := strings.TrimPrefix(part.Value, "file,")
+				if i := strings.Index(src, ";"); i >= 0 {
+					src = src[:i]
+				}
+				body.FormData = append(body.FormData, postmanFormPart{Key: part.Key, Type: "file", Src: src})
+				continue
+			}
+			body.FormData = append(body.FormData, postmanFormPart{Key: part.Key, Value: convert(part.Value), Type: "text"})
+		}
+		req.Body = body
+	case entry.File != "":
+		req.Body = &postmanBody{Mode: "file", File: &postmanFile{Src: entry.File}}
+	case len(entry.Body) > 0:
+		raw := convert(strings.Join(entry.Body, "\n"))
+		body := &postmanBody{Mode: "raw", Raw: raw}
+		first := strings.TrimSpace(raw)
+		if strings.HasPrefix(first, "{") || strings.HasPrefix(first, "[") {
+			body.Options = json.RawMessage(`{"raw":{"language":"json"}}`)
+		}
+		req.Body = body
+	}
+
+	item := postmanItem{Name: name, Request: req}
+	if script := postmanTestScript(entry, file, result); len(script) > 0 {
+		item.Event = []postmanEvent{{
+			Listen: "test",
+			Script: postmanScript{Type: "text/javascript", Exec: script},
+		}}
+	}
+
+	return item
+}
+
+// postmanTestScript expresses the entry's status line and captures as a Postman test script
+func postmanTestScript(entry *hurlScanEntry, file string, result *ExportResult) []string {
+	var script []string
+
+	if entry.Status != "" && entry.Status != "*" {
+		script = append(script,
+			fmt.Sprintf("pm.test(\"Status code is %s\", function () {", entry.Status),
+			fmt.Sprintf("    pm.response.to.have.status(%s);", entry.Status),
+			"});",
+		)
+	}
+
+	for _, c := range entry.Captures {
+		switch c.Kind {
+		case "jsonpath":
+			path, ok := jsonPathToJS(c.Expr)
+			if !ok {
+				result.Issues = append(result.Issues, ConversionIssue{
+					Line:      c.Line,
+					Construct: "capture",
+					Message:   fmt.Sprintf("%s: capture %s uses a JSONPath that cannot be expressed in a test script", file, c.Name),
+				})
+				continue
+			}
+			script = append(script, fmt.Sprintf("pm.environment.set(%q, pm.response.json()%s);", c.Name, path))
+		case "header":
+			script = append(script, fmt.Sprintf("pm.environment.set(%q, pm.response.headers.get(%q));", c.Name, c.Expr))
+		default:
+			result.Issues = append(result.Issues, ConversionIssue{
+				Line:      c.Line,
+				Construct: "capture",
+				Message:   fmt.Sprintf("%s: capture %s is not exported", file, c.Name),
+			})
+		}
+	}
+
+	return script
+}
+
+// jsonPathToJS converts a simple JSONPath such as $.data[0].id to a JavaScript property path
+func jsonPathToJS(path string) (string, bool) {
+	if !strings.HasPrefix(path, "$") {
+		return "", false
+	}
+	rest := path[1:]
+	if strings.ContainsAny(rest, "*?@()") || strings.Contains(rest, "..") {
+		return "", false
+	}
+	return rest, true
+}
+
+// writeJSONFile writes v as indented JSON to a new file at path
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	return writeNewFile(path, string(data))
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// sortedKeys returns the keys of a string map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
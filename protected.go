@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// allowMutationsTag is the comment that lets mutating requests run against a
+// protected environment blocking them. In the comments of the file header it
+// allows every entry, right above a request line only that entry.
+const allowMutationsTag = "# @allow-mutations"
+
+// mutatingMethods are the methods blocked by EnvironmentProtection.BlockMutations
+var mutatingMethods = []string{"POST", "PUT", "PATCH", "DELETE"}
+
+// EnvironmentProtection marks an environment whose runs need confirmation
+type EnvironmentProtection struct {
+	// BlockMutations refuses entries with a mutating method, unless the file
+	// carries allowMutationsTag
+	BlockMutations bool `json:"blockMutations,omitempty"`
+}
+
+// AuditEntry records a run against a protected environment
+type AuditEntry struct {
+	Time        time.Time `json:"time"`
+	User        string    `json:"user"`
+	Environment string    `json:"environment"`
+	File        string    `json:"file"`
+	// Entries are the 1-based entries run, empty for the whole file
+	Entries []int `json:"entries,omitempty"`
+	// Outcome is confirmed, cancelled or blocked
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// getAuditLogPath returns the path to the audit log of protected runs
+func getAuditLogPath() (string, error) {
+	envFilePath, err := getEnvFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(envFilePath), "audit.log"), nil
+}
+
+// recordAudit appends an entry to the audit log, one JSON object per line
+func recordAudit(entry AuditEntry) error {
+	path, err := getAuditLogPath()
+	if err != nil {
+		return err
+	}
+	if current, err := user.Current(); err == nil {
+		entry.User = current.Username
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// environmentProtection returns the strictest protection of an environment
+// and the environments it extends, nil when none of them is protected
+func environmentProtection(config *EnvConfig, environment string) (*EnvironmentProtection, error) {
+	chain, err := environmentChain(config, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	var protection *EnvironmentProtection
+	for _, env := range chain {
+		p, ok := config.Protected[env]
+		if !ok {
+			continue
+		}
+		if protection == nil {
+			protection = &EnvironmentProtection{}
+		}
+		protection.BlockMutations = protection.BlockMutations || p.BlockMutations
+	}
+	return protection, nil
+}
+
+// checkProtectedRun asks for confirmation before running entries of a file
+// against a protected active environment, and refuses mutating entries when
+// the environment blocks them. An environment extending a protected one is
+// protected too. entries are 1-based, nil meaning the whole file. Every
+// decision is recorded in the audit log, a run that cannot be recorded or
+// checked, such as a file with syntax errors, does not happen.
+func (a *App) checkProtectedRun(filePath string, entries []int) error {
+	config, err := a.loadEnvConfig()
+	if err != nil {
+		return fmt.Errorf("cannot check whether the active environment is protected: %w", err)
+	}
+	environment := config.ActiveEnvironment
+	protection, err := environmentProtection(config, environment)
+	if err != nil {
+		return fmt.Errorf("cannot check whether environment %s is protected: %w", environment, err)
+	}
+	if protection == nil {
+		return nil
+	}
+
+	record := AuditEntry{Time: time.Now(), Environment: environment, File: filePath, Entries: entries}
+	refuse := func(outcome string, err error) error {
+		record.Outcome = outcome
+		record.Reason = err.Error()
+		if auditErr := recordAudit(record); auditErr != nil {
+			return fmt.Errorf("%w (%v)", err, auditErr)
+		}
+		return err
+	}
+
+	content, err := a.GetFileContent(filePath)
+	if err != nil {
+		return err
+	}
+	// hurl may run requests the parser did not find
+	file := parseHurl(content)
+	if len(file.Errors) > 0 {
+		e := file.Errors[0]
+		return refuse("blocked", fmt.Errorf("%s is protected and %s has a syntax error at line %d: %s", environment, filepath.Base(filePath), e.Span.Start.Line, e.Message))
+	}
+
+	if protection.BlockMutations {
+		if mutating := mutatingEntries(content, file, entries); len(mutating) > 0 {
+			return refuse("blocked", fmt.Errorf("%s is protected against mutating methods: %s, add a %q comment to the file header or above the request line to allow them", environment, strings.Join(mutating, ", "), allowMutationsTag))
+		}
+	}
+
+	confirmed, err := a.confirmProtectedRun(environment, filePath)
+	if err != nil {
+		return refuse("cancelled", err)
+	}
+	if !confirmed {
+		return refuse("cancelled", fmt.Errorf("run against protected environment %s was cancelled", environment))
+	}
+
+	record.Outcome = "confirmed"
+	if err := recordAudit(record); err != nil {
+		return fmt.Errorf("cannot run against protected environment %s: %w", environment, err)
+	}
+	return nil
+}
+
+// mutatingEntries describes the entries of file with a mutating method, among
+// entries or in the whole file when entries is nil. Entries allowed by
+// allowMutationsTag are left out.
+func mutatingEntries(content string, file *HurlFile, entries []int) []string {
+	if len(file.Entries) == 0 {
+		return nil
+	}
+
+	// The file header is the comments before the first entry's own comments
+	header := file.Entries[0].Request.Span.Start.Line - 1
+	if len(file.Entries[0].Comments) > 0 {
+		header = file.Entries[0].Comments[0].Span.Start.Line - 1
+	}
+	lines := strings.Split(content, "\n")
+	for _, line := range lines[:header] {
+		if strings.TrimSpace(line) == allowMutationsTag {
+			return nil
+		}
+	}
+	tag := strings.TrimSpace(strings.TrimPrefix(allowMutationsTag, "#"))
+
+	var mutating []string
+	for _, entry := range file.Entries {
+		if entries != nil && !slices.Contains(entries, entry.Index) {
+			continue
+		}
+		allowed := slices.ContainsFunc(entry.Comments, func(c HurlComment) bool { return c.Text == tag })
+		if !allowed && slices.Contains(mutatingMethods, strings.ToUpper(entry.Request.Method)) {
+			mutating = append(mutating, fmt.Sprintf("entry %d (%s %s)", entry.Index, entry.Request.Method, entry.Request.URL))
+		}
+	}
+	return mutating
+}
+
+// confirmProtectedRun asks the user to confirm a run with a native dialog
+func (a *App) confirmProtectedRun(environment string, filePath string) (bool, error) {
+	if a.ctx == nil {
+		return false, fmt.Errorf("cannot ask for confirmation to run against protected environment %s", environment)
+	}
+
+	answer, err := runtime.MessageDialog(a.ctx, runtime.MessageDialogOptions{
+		Type:          runtime.QuestionDialog,
+		Title:         "Protected environment",
+		Message:       fmt.Sprintf("%s is a protected environment. Run %s against it?", environment, filepath.Base(filePath)),
+		Buttons:       []string{"Yes", "No"},
+		DefaultButton: "No",
+		CancelButton:  "No",
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to ask for confirmation: %w", err)
+	}
+	return answer == "Yes", nil
+}
+
+// SetEnvironmentProtection protects an environment of the user env.json or
+// removes its protection. Environments protected by the project file, or
+// extending a protected environment, stay protected.
+func (a *App) SetEnvironmentProtection(environment string, protected bool, blockMutations bool) error {
+	return a.updateEnvConfig(func(config *EnvConfig) error {
+		if exists, err := a.environmentExists(config, environment); err != nil {
+			return err
+		} else if !exists {
+			return fmt.Errorf("environment %s does not exist", environment)
+		}
+		if !protected {
+			delete(config.Protected, environment)
+			return nil
+		}
+		if config.Protected == nil {
+			config.Protected = make(map[string]EnvironmentProtection)
+		}
+		config.Protected[environment] = EnvironmentProtection{BlockMutations: blockMutations}
+		return nil
+	})
+}
+
+// GetEnvironmentProtection returns the protection of an environment, including
+// the protection of the environments it extends, nil when it is not protected
+func (a *App) GetEnvironmentProtection(environment string) (*EnvironmentProtection, error) {
+	config, err := a.loadEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+	return environmentProtection(config, environment)
+}
+
+// GetAuditLog returns the latest runs against protected environments, newest first
+func (a *App) GetAuditLog(limit int) ([]AuditEntry, error) {
+	path, err := getAuditLogPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []AuditEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	entries := []AuditEntry{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	slices.Reverse(entries)
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMutatingEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		entries []int
+		want    []string
+	}{
+		{
+			name:    "mutating methods",
+			content: "GET https://a\n\nPOST https://b\n\nDELETE https://c\n",
+			want:    []string{"entry 2 (POST https://b)", "entry 3 (DELETE https://c)"},
+		},
+		{
+			name:    "selected entries only",
+			content: "POST https://a\n\nGET https://b\n\nPUT https://c\n",
+			entries: []int{2, 3},
+			want:    []string{"entry 3 (PUT https://c)"},
+		},
+		{
+			name:    "tag in the file header",
+			content: "# Seed data\n# @allow-mutations\n\nPOST https://a\n\nDELETE https://b\n",
+		},
+		{
+			name:    "tag above an entry",
+			content: "# @allow-mutations\nPOST https://a\n\nDELETE https://b\n",
+			want:    []string{"entry 2 (DELETE https://b)"},
+		},
+		{
+			name:    "tag above a later entry",
+			content: "POST https://a\n\n# @allow-mutations\nDELETE https://b\n",
+			want:    []string{"entry 1 (POST https://a)"},
+		},
+		{
+			name:    "tag in a body",
+			content: "POST https://a\n```\n# @allow-mutations\n```\n",
+			want:    []string{"entry 1 (POST https://a)"},
+		},
+		{
+			name:    "tag after a response",
+			content: "POST https://a\nHTTP 200\n# @allow-mutations\n\nPUT https://b\n",
+			want:    []string{"entry 1 (POST https://a)", "entry 2 (PUT https://b)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := parseHurl(tt.content)
+			if len(file.Errors) > 0 {
+				t.Fatalf("unexpected parse errors: %v", file.Errors)
+			}
+			if got := mutatingEntries(tt.content, file, tt.entries); !slices.Equal(got, tt.want) {
+				t.Errorf("mutatingEntries() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckProtectedRun(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	a := NewApp()
+	steps := []func() error{
+		func() error { return a.CreateEnvironment("prod", "") },
+		func() error { return a.CreateEnvironment("prod-eu", "prod") },
+		func() error { return a.CreateEnvironment("dev", "") },
+		func() error { return a.SetEnvironmentProtection("prod", true, true) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		environment string
+		content     string
+		err         string
+		outcome     string
+	}{
+		{name: "unprotected environment", environment: "dev", content: "POST https://a\n"},
+		{name: "mutating entry", environment: "prod", content: "POST https://a\n", err: "prod is protected against mutating methods: entry 1 (POST https://a)", outcome: "blocked"},
+		{name: "extending a protected environment", environment: "prod-eu", content: "DELETE https://a\n", err: "prod-eu is protected against mutating methods", outcome: "blocked"},
+		{name: "syntax error", environment: "prod", content: "GET https://a\nnot a request\n", err: "has a syntax error at line 2", outcome: "blocked"},
+		// Without a window the run cannot be confirmed
+		{name: "confirmation", environment: "prod", content: "GET https://a\n", err: "cannot ask for confirmation", outcome: "cancelled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := a.SetActiveEnvironment(tt.environment); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "run.hurl")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := a.checkProtectedRun(path, nil)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("error = %v, want %q", err, tt.err)
+			}
+
+			audit, err := a.GetAuditLog(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(audit) != 1 || audit[0].File != path || audit[0].Outcome != tt.outcome {
+				t.Errorf("audit log = %+v, want a %s run of %s", audit, tt.outcome, path)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RenameChange is the effect of a rename on one file
+type RenameChange struct {
+	Path        string `json:"path"`
+	Diff        string `json:"diff"`
+	Occurrences int    `json:"occurrences"`
+}
+
+// RenamePreview lists the changes a rename would make. The rename cannot be
+// applied while Conflicts is not empty.
+type RenamePreview struct {
+	OldName   string         `json:"oldName"`
+	NewName   string         `json:"newName"`
+	Changes   []RenameChange `json:"changes"`
+	Conflicts []string       `json:"conflicts"`
+}
+
+// renamePlan holds the new content of every file touched by a rename
+type renamePlan struct {
+	preview *RenamePreview
+	files   map[string]string
+	config  *EnvConfig
+	// secret is set when the variable is a secret of the vault
+	secret bool
+}
+
+// PreviewRenameVariable shows how renaming a variable would change env.json
+// and the .hurl files below dirPath, without writing anything
+func (a *App) PreviewRenameVariable(dirPath string, oldName string, newName string) (*RenamePreview, error) {
+	plan, err := a.planRename(dirPath, oldName, newName)
+	if err != nil {
+		return nil, err
+	}
+	return plan.preview, nil
+}
+
+// ApplyRenameVariable renames a variable in every environment and in every
+// usage, capture and option of the .hurl files below dirPath. Either every
+// file is updated or none is.
+func (a *App) ApplyRenameVariable(dirPath string, oldName string, newName string) error {
+	// The plan is made and applied under the lock, so env.json does not change in between
+	a.envWriteMu.Lock()
+	defer a.envWriteMu.Unlock()
+
+	plan, err := a.planRename(dirPath, oldName, newName)
+	if err != nil {
+		return err
+	}
+	if len(plan.preview.Conflicts) > 0 {
+		return fmt.Errorf("cannot rename %s to %s: %s", oldName, newName, strings.Join(plan.preview.Conflicts, "; "))
+	}
+
+	for path, content := range plan.files {
+		if a.isWatchedEnvFile(path) {
+			a.rememberEnvFile(path, []byte(content))
+		}
+	}
+	rollback, err := writeFilesAtomically(plan.files)
+	if err != nil {
+		return err
+	}
+
+	// The secret is renamed first so env.json is saved without its masked value
+	if plan.secret {
+		undo, err := a.renameSecret(oldName, newName)
+		if err != nil {
+			if rollbackErr := rollback(); rollbackErr != nil {
+				return fmt.Errorf("%w (restoring files also failed: %v)", err, rollbackErr)
+			}
+			return err
+		}
+		previous := rollback
+		rollback = func() error {
+			if err := undo(); err != nil {
+				return err
+			}
+			return previous()
+		}
+	}
+
+	if plan.config != nil {
+		if err := a.writeEnvConfig(plan.config); err != nil {
+			if rollbackErr := rollback(); rollbackErr != nil {
+				return fmt.Errorf("%w (restoring files also failed: %v)", err, rollbackErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *App) planRename(dirPath string, oldName string, newName string) (*renamePlan, error) {
+	if oldName == "" || newName == "" {
+		return nil, fmt.Errorf("variable names cannot be empty")
+	}
+	if sanitizeVariableName(newName) != newName {
+		return nil, fmt.Errorf("invalid variable name %q", newName)
+	}
+	if oldName == newName {
+		return nil, fmt.Errorf("%s already has that name", oldName)
+	}
+
+	plan := &renamePlan{
+		preview: &RenamePreview{OldName: oldName, NewName: newName, Changes: []RenameChange{}, Conflicts: []string{}},
+		files:   make(map[string]string),
+	}
+
+	if err := a.planEnvRename(plan, oldName, newName); err != nil {
+		return nil, err
+	}
+
+	files, err := collectHurlFiles(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		content, err := a.GetFileContent(path)
+		if err != nil {
+			return nil, err
+		}
+
+		file := parseHurl(content)
+		var spans []Span
+		uses := false
+		for _, d := range hurlDefinitions(file) {
+			switch d.Name {
+			case oldName:
+				spans = append(spans, d.NameSpan)
+			case newName:
+				uses = true
+			}
+		}
+		for _, usage := range file.Variables {
+			switch usage.Name {
+			case oldName:
+				spans = append(spans, usage.Span)
+			case newName:
+				uses = true
+			}
+		}
+		if len(spans) == 0 {
+			continue
+		}
+		if uses {
+			plan.preview.Conflicts = append(plan.preview.Conflicts, fmt.Sprintf("%s already uses %s", path, newName))
+		}
+
+		renamed := replaceInSpans(content, spans, oldName, newName)
+		plan.files[path] = renamed
+		plan.preview.Changes = append(plan.preview.Changes, RenameChange{
+			Path:        path,
+			Diff:        unifiedDiff(path, content, renamed),
+			Occurrences: len(spans),
+		})
+	}
+
+	return plan, nil
+}
+
+// planEnvRename renames the key in the global variables and every environment
+// of the user env.json and of the project env.json of the workspace
+func (a *App) planEnvRename(plan *renamePlan, oldName string, newName string) error {
+	layers, err := a.envLayers()
+	if err != nil {
+		return err
+	}
+
+	// Environments override the global variables and the layers override each
+	// other, so an existing definition of the new name anywhere would silently
+	// merge two variables
+	total := 0
+	var conflicts []string
+	for _, layer := range layers {
+		config := layer.Config
+		environments := make([]string, 0, len(config.Environments))
+		for env := range config.Environments {
+			environments = append(environments, env)
+		}
+		sort.Strings(environments)
+
+		occurrences, secrets := 0, 0
+		rename := func(scope string, vars map[string]string) {
+			if layer.Name == "project" {
+				scope = "project " + scope
+			}
+			if _, exists := vars[newName]; exists {
+				conflicts = append(conflicts, fmt.Sprintf("%s already defines %s", scope, newName))
+			}
+			value, ok := vars[oldName]
+			if !ok {
+				return
+			}
+			delete(vars, oldName)
+			vars[newName] = value
+			if value == secretMask && layer.Name == "user" {
+				secrets++
+			} else {
+				occurrences++
+			}
+		}
+
+		rename("global", config.Global)
+		for _, env := range environments {
+			rename("environment "+env, config.Environments[env])
+		}
+		// The type follows the variable
+		if variableType, ok := config.Types[oldName]; ok {
+			delete(config.Types, oldName)
+			config.Types[newName] = variableType
+			occurrences++
+		}
+		total += occurrences + secrets
+
+		// Secrets are renamed in the vault, which has no readable diff
+		if secrets > 0 {
+			vaultPath, err := getVaultFilePath()
+			if err != nil {
+				return err
+			}
+			plan.secret = true
+			plan.preview.Changes = append(plan.preview.Changes, RenameChange{Path: vaultPath, Occurrences: secrets})
+		}
+		if occurrences == 0 {
+			continue
+		}
+
+		before, err := os.ReadFile(layer.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", layer.Path, err)
+		}
+		// writeEnvConfig writes indented JSON without the secrets, so the
+		// diff matches what will be saved
+		after, err := json.MarshalIndent(withoutSecrets(config), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+
+		// The user file is saved through writeEnvConfig, the project file
+		// is written together with the .hurl files
+		if layer.Name == "user" {
+			plan.config = config
+		} else {
+			plan.files[layer.Path] = string(after)
+		}
+		plan.preview.Changes = append(plan.preview.Changes, RenameChange{
+			Path:        layer.Path,
+			Diff:        unifiedDiff(layer.Path, string(before), string(after)),
+			Occurrences: occurrences,
+		})
+	}
+
+	if total > 0 {
+		plan.preview.Conflicts = append(plan.preview.Conflicts, conflicts...)
+	}
+	if plan.secret {
+		if status, err := a.GetVaultStatus(); err != nil || !status.Unlocked {
+			plan.preview.Conflicts = append(plan.preview.Conflicts, fmt.Sprintf("%s is a secret, unlock the secrets vault to rename it", oldName))
+		}
+	}
+	return nil
+}
+
+// replaceInSpans replaces the first occurrence of oldText inside each span of content
+func replaceInSpans(content string, spans []Span, oldText string, newText string) string {
+	lines := strings.Split(content, "\n")
+
+	// Later spans are replaced first so earlier columns stay valid
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].Start.Line != spans[j].Start.Line {
+			return spans[i].Start.Line > spans[j].Start.Line
+		}
+		return spans[i].Start.Column > spans[j].Start.Column
+	})
+
+	for _, span := range spans {
+		if span.Start.Line != span.End.Line || span.Start.Line > len(lines) {
+			continue
+		}
+		line := lines[span.Start.Line-1]
+		start, end := byteOffset(line, span.Start.Column), byteOffset(line, span.End.Column)
+		lines[span.Start.Line-1] = line[:start] + strings.Replace(line[start:end], oldText, newText, 1) + line[end:]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// byteOffset converts a 1-based character column of line to a byte offset
+func byteOffset(line string, column int) int {
+	col := 1
+	for i := range line {
+		if col == column {
+			return i
+		}
+		col++
+	}
+	return len(line)
+}
+
+// writeFilesAtomically writes every file or none of them. New contents are
+// written to temporary files first and then renamed into place. The returned
+// function restores the previous contents.
+func writeFilesAtomically(files map[string]string) (func() error, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	originals := make(map[string][]byte)
+	temps := make(map[string]string)
+	cleanup := func() {
+		for _, tmp := range temps {
+			os.Remove(tmp)
+		}
+	}
+
+	for _, path := range paths {
+		original, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			cleanup()
+			return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		originals[path] = original
+
+		tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to create temporary file for %s: %w", path, err)
+		}
+		temps[path] = tmp.Name()
+		// Keep the permissions of the file being replaced
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		_, writeErr := tmp.WriteString(files[path])
+		closeErr := tmp.Close()
+		chmodErr := os.Chmod(tmp.Name(), mode)
+		if writeErr != nil || closeErr != nil || chmodErr != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to write temporary file for %s", path)
+		}
+	}
+
+	restore := func(done []string) error {
+		var failed []string
+		for _, path := range done {
+			if err := os.WriteFile(path, originals[path], 0644); err != nil {
+				failed = append(failed, path)
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("failed to restore %s", strings.Join(failed, ", "))
+		}
+		return nil
+	}
+
+	var done []string
+	for _, path := range paths {
+		if err := os.Rename(temps[path], path); err != nil {
+			cleanup()
+			if restoreErr := restore(done); restoreErr != nil {
+				return nil, fmt.Errorf("failed to replace %s: %w (%v)", path, err, restoreErr)
+			}
+			return nil, fmt.Errorf("failed to replace %s: %w", path, err)
+		}
+		delete(temps, path)
+		done = append(done, path)
+	}
+
+	return func() error { return restore(done) }, nil
+}
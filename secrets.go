@@ -0,0 +1,645 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// secretMask replaces the value of secret variables in config returned to the UI
+const secretMask = "********"
+
+// vaultFile is the on-disk format of the secrets vault. Data is the AES-GCM
+// encryption of a vaultSecrets with a key derived from the passphrase. Names
+// are kept in clear so secrets can be masked while the vault is locked, and
+// are authenticated along with Data.
+type vaultFile struct {
+	Version int        `json:"version"`
+	Salt    []byte     `json:"salt"`
+	Nonce   []byte     `json:"nonce"`
+	Data    []byte     `json:"data"`
+	Names   vaultNames `json:"names"`
+}
+
+// vaultNames lists the secret variables by scope
+type vaultNames struct {
+	Global       []string            `json:"global"`
+	Environments map[string][]string `json:"environments"`
+}
+
+// vaultSecrets holds the decrypted values of the secret variables
+type vaultSecrets struct {
+	Global       map[string]string            `json:"global"`
+	Environments map[string]map[string]string `json:"environments"`
+}
+
+// VaultStatus tells the UI whether a vault exists and is unlocked for this session
+type VaultStatus struct {
+	Exists   bool `json:"exists"`
+	Unlocked bool `json:"unlocked"`
+}
+
+// getVaultFilePath returns the path to the secrets vault next to env.json
+func getVaultFilePath() (string, error) {
+	envFilePath, err := getEnvFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(envFilePath), "secrets.json"), nil
+}
+
+// readVaultFile reads the vault without decrypting it. It returns nil when
+// there is no vault yet.
+func readVaultFile() (*vaultFile, error) {
+	path, err := getVaultFilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets vault: %w", err)
+	}
+
+	var vault vaultFile
+	if err := json.Unmarshal(data, &vault); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets vault: %w", err)
+	}
+	return &vault, nil
+}
+
+// deriveVaultKey turns a passphrase into an AES-256 key
+func deriveVaultKey(passphrase string, salt []byte) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return key, nil
+}
+
+func newVaultCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// decrypt opens the vault with key
+func (v *vaultFile) decrypt(key []byte) (*vaultSecrets, error) {
+	gcm, err := newVaultCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	names, err := json.Marshal(v.Names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal secret names: %w", err)
+	}
+	plain, err := gcm.Open(nil, v.Nonce, v.Data, names)
+	if err != nil {
+		return nil, fmt.Errorf("wrong passphrase or corrupted secrets vault")
+	}
+
+	var secrets vaultSecrets
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets: %w", err)
+	}
+	if secrets.Global == nil {
+		secrets.Global = make(map[string]string)
+	}
+	if secrets.Environments == nil {
+		secrets.Environments = make(map[string]map[string]string)
+	}
+	return &secrets, nil
+}
+
+// GetVaultStatus reports whether the secrets vault exists and is unlocked
+func (a *App) GetVaultStatus() (VaultStatus, error) {
+	vault, err := readVaultFile()
+	if err != nil {
+		return VaultStatus{}, err
+	}
+
+	a.vaultMu.Lock()
+	defer a.vaultMu.Unlock()
+	return VaultStatus{Exists: vault != nil, Unlocked: a.vaultKey != nil}, nil
+}
+
+// UnlockVault unlocks the secrets vault for the rest of the session. The
+// vault is created with this passphrase when it does not exist yet.
+func (a *App) UnlockVault(passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("passphrase cannot be empty")
+	}
+
+	vault, err := readVaultFile()
+	if err != nil {
+		return err
+	}
+
+	if vault == nil {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+		key, err := deriveVaultKey(passphrase, salt)
+		if err != nil {
+			return err
+		}
+		vault = &vaultFile{Version: 1, Salt: salt}
+		secrets := &vaultSecrets{Global: map[string]string{}, Environments: map[string]map[string]string{}}
+		if err := writeVault(vault, secrets, key); err != nil {
+			return err
+		}
+		a.setVaultKey(key)
+		return nil
+	}
+
+	key, err := deriveVaultKey(passphrase, vault.Salt)
+	if err != nil {
+		return err
+	}
+	if _, err := vault.decrypt(key); err != nil {
+		return err
+	}
+	a.setVaultKey(key)
+	return nil
+}
+
+// LockVault forgets the vault key, secrets cannot be used until the next unlock
+func (a *App) LockVault() {
+	a.setVaultKey(nil)
+}
+
+func (a *App) setVaultKey(key []byte) {
+	a.vaultMu.Lock()
+	defer a.vaultMu.Unlock()
+	a.vaultKey = key
+}
+
+// openVault decrypts the vault with the session key
+func (a *App) openVault() (*vaultFile, *vaultSecrets, []byte, error) {
+	a.vaultMu.Lock()
+	key := a.vaultKey
+	a.vaultMu.Unlock()
+	if key == nil {
+		return nil, nil, nil, fmt.Errorf("the secrets vault is locked")
+	}
+
+	vault, err := readVaultFile()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if vault == nil {
+		return nil, nil, nil, fmt.Errorf("the secrets vault does not exist")
+	}
+	secrets, err := vault.decrypt(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return vault, secrets, key, nil
+}
+
+// writeVault encrypts secrets into the vault file, readable by the user only
+func writeVault(vault *vaultFile, secrets *vaultSecrets, key []byte) error {
+	vault.Names = vaultNames{Global: slices.Sorted(maps.Keys(secrets.Global)), Environments: map[string][]string{}}
+	for env, vars := range secrets.Environments {
+		if len(vars) == 0 {
+			delete(secrets.Environments, env)
+			continue
+		}
+		vault.Names.Environments[env] = slices.Sorted(maps.Keys(vars))
+	}
+
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets: %w", err)
+	}
+	names, err := json.Marshal(vault.Names)
+	if err != nil {
+		return fmt.Errorf("failed to marshal secret names: %w", err)
+	}
+	gcm, err := newVaultCipher(key)
+	if err != nil {
+		return err
+	}
+	vault.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(vault.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	vault.Data = gcm.Seal(nil, vault.Nonce, plain, names)
+
+	data, err := json.MarshalIndent(vault, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets vault: %w", err)
+	}
+	path, err := getVaultFilePath()
+	if err != nil {
+		return err
+	}
+
+	// CreateTemp makes the file readable by the user only
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".secrets.*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for secrets vault: %w", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write secrets vault")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write secrets vault: %w", err)
+	}
+	return nil
+}
+
+// secretScope returns the variables of a scope, "" meaning global
+func (s *vaultSecrets) secretScope(environment string) map[string]string {
+	if environment == "" {
+		return s.Global
+	}
+	if s.Environments[environment] == nil {
+		s.Environments[environment] = make(map[string]string)
+	}
+	return s.Environments[environment]
+}
+
+// SetSecret stores a secret variable in the vault. An empty environment
+// means a global variable. A plaintext variable of the same name in env.json
+// is removed.
+func (a *App) SetSecret(environment string, name string, value string) error {
+	if name == "" || sanitizeVariableName(name) != name {
+		return fmt.Errorf("invalid variable name %q", name)
+	}
+
+	a.envWriteMu.Lock()
+	defer a.envWriteMu.Unlock()
+	return a.setSecret(environment, name, value)
+}
+
+// setSecret is SetSecret for callers holding envWriteMu
+func (a *App) setSecret(environment string, name string, value string) error {
+	undo, err := a.updateVault(func(secrets *vaultSecrets) {
+		secrets.secretScope(environment)[name] = value
+	})
+	if err != nil {
+		return err
+	}
+
+	// The masked value is dropped when env.json is written
+	config, err := a.readUserEnvConfig()
+	if err != nil {
+		return err
+	}
+	if vars := configScope(config, environment); vars != nil {
+		if _, ok := vars[name]; ok {
+			vars[name] = secretMask
+			if err := a.writeEnvConfig(config); err != nil {
+				if undoErr := undo(); undoErr != nil {
+					return fmt.Errorf("%w (restoring secrets also failed: %v)", err, undoErr)
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MarkSecret moves a plaintext variable of env.json into the vault
+func (a *App) MarkSecret(environment string, name string) error {
+	a.envWriteMu.Lock()
+	defer a.envWriteMu.Unlock()
+
+	config, err := a.readUserEnvConfig()
+	if err != nil {
+		return err
+	}
+	value, ok := configScope(config, environment)[name]
+	if !ok {
+		return fmt.Errorf("variable %s does not exist", name)
+	}
+	if value == secretMask {
+		return fmt.Errorf("variable %s is already a secret", name)
+	}
+	return a.setSecret(environment, name, value)
+}
+
+// UnmarkSecret moves a secret back to env.json as a plaintext variable
+func (a *App) UnmarkSecret(environment string, name string) error {
+	a.envWriteMu.Lock()
+	defer a.envWriteMu.Unlock()
+
+	var value string
+	var ok bool
+	undo, err := a.updateVault(func(secrets *vaultSecrets) {
+		scope := secrets.secretScope(environment)
+		value, ok = scope[name]
+		delete(scope, name)
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("variable %s is not a secret", name)
+	}
+
+	config, err := a.readUserEnvConfig()
+	if err != nil {
+		return err
+	}
+	vars := configScope(config, environment)
+	if vars == nil {
+		vars = make(map[string]string)
+		config.Environments[environment] = vars
+	}
+	vars[name] = value
+	if err := a.writeEnvConfig(config); err != nil {
+		if undoErr := undo(); undoErr != nil {
+			return fmt.Errorf("%w (restoring secrets also failed: %v)", err, undoErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteSecret removes a secret variable from the vault
+func (a *App) DeleteSecret(environment string, name string) error {
+	a.envWriteMu.Lock()
+	defer a.envWriteMu.Unlock()
+
+	vault, secrets, key, err := a.openVault()
+	if err != nil {
+		return err
+	}
+	scope := secrets.secretScope(environment)
+	if _, ok := scope[name]; !ok {
+		return fmt.Errorf("variable %s is not a secret", name)
+	}
+	delete(scope, name)
+	if err := writeVault(vault, secrets, key); err != nil {
+		return err
+	}
+	a.invalidateEnvCache()
+	return nil
+}
+
+// configScope returns the variables of a scope of config, "" meaning global
+func configScope(config *EnvConfig, environment string) map[string]string {
+	if environment == "" {
+		return config.Global
+	}
+	return config.Environments[environment]
+}
+
+// maskSecrets adds the secret variables to env.json content with a masked value
+func maskSecrets(content []byte) (string, error) {
+	vault, err := readVaultFile()
+	if err != nil || vault == nil {
+		return string(content), err
+	}
+
+	var config EnvConfig
+	if err := json.Unmarshal(content, &config); err != nil {
+		return "", fmt.Errorf("failed to parse env.json: %w", err)
+	}
+	if config.Global == nil {
+		config.Global = make(map[string]string)
+	}
+	if config.Environments == nil {
+		config.Environments = make(map[string]map[string]string)
+	}
+
+	for _, name := range vault.Names.Global {
+		config.Global[name] = secretMask
+	}
+	for env, names := range vault.Names.Environments {
+		if config.Environments[env] == nil {
+			config.Environments[env] = make(map[string]string)
+		}
+		for _, name := range names {
+			config.Environments[env][name] = secretMask
+		}
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return string(data), nil
+}
+
+// stripSecrets removes the masked secret variables from config before it is
+// written to env.json. Secrets cannot be changed through env.json.
+func stripSecrets(config *EnvConfig) error {
+	vault, err := readVaultFile()
+	if err != nil {
+		return err
+	}
+
+	strip := func(vars map[string]string, names []string) error {
+		for _, name := range names {
+			value, ok := vars[name]
+			if !ok {
+				continue
+			}
+			if value != secretMask {
+				return fmt.Errorf("%s is a secret, change it with the secrets vault", name)
+			}
+			delete(vars, name)
+		}
+		for name, value := range vars {
+			if value == secretMask {
+				return fmt.Errorf("%s has a masked value but is not a secret", name)
+			}
+		}
+		return nil
+	}
+
+	var names vaultNames
+	if vault != nil {
+		names = vault.Names
+	}
+	if err := strip(config.Global, names.Global); err != nil {
+		return err
+	}
+	for env, vars := range config.Environments {
+		if err := strip(vars, names.Environments[env]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutSecrets returns a copy of config without the masked secret variables
+func withoutSecrets(config *EnvConfig) *EnvConfig {
+	stripped := *config
+	stripped.Global = make(map[string]string)
+	stripped.Environments = make(map[string]map[string]string)
+	for k, v := range config.Global {
+		if v != secretMask {
+			stripped.Global[k] = v
+		}
+	}
+	for env, vars := range config.Environments {
+		stripped.Environments[env] = make(map[string]string)
+		for k, v := range vars {
+			if v != secretMask {
+				stripped.Environments[env][k] = v
+			}
+		}
+	}
+	return &stripped
+}
+
+// renameSecret renames a secret in every scope of the vault. The returned
+// function renames it back. Callers hold envWriteMu.
+func (a *App) renameSecret(oldName string, newName string) (func() error, error) {
+	move := func(from string, to string) error {
+		vault, secrets, key, err := a.openVault()
+		if err != nil {
+			return err
+		}
+		scopes := []map[string]string{secrets.Global}
+		for _, vars := range secrets.Environments {
+			scopes = append(scopes, vars)
+		}
+		for _, vars := range scopes {
+			if value, ok := vars[from]; ok {
+				delete(vars, from)
+				vars[to] = value
+			}
+		}
+		if err := writeVault(vault, secrets, key); err != nil {
+			return err
+		}
+		a.invalidateEnvCache()
+		return nil
+	}
+
+	if err := move(oldName, newName); err != nil {
+		return nil, err
+	}
+	return func() error { return move(newName, oldName) }, nil
+}
+
+// secretArgs replaces the masked values among raw variables with the
+// decrypted secrets of an environment, then resolves references. The text of
+// a secret is taken literally. Secrets and the variables built from them are
+// removed from vars and returned as --secret arguments for hurl.
+func (a *App) secretArgs(environment string, vars map[string]string) ([]string, error) {
+	secretNames := make(map[string]bool)
+	for name, value := range vars {
+		if value == secretMask {
+			secretNames[name] = true
+		}
+	}
+
+	config, err := a.loadEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secretNames) > 0 {
+		_, secrets, _, err := a.openVault()
+		if err != nil {
+			return nil, fmt.Errorf("cannot use secret variables: %w", err)
+		}
+		chain, err := environmentChain(config, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		for name := range secretNames {
+			// The closest environment defining the secret wins, then global
+			value, ok := secrets.Global[name]
+			for i := len(chain) - 1; i >= 0; i-- {
+				if v, found := secrets.Environments[chain[i]][name]; found {
+					value, ok = v, true
+					break
+				}
+			}
+			if !ok {
+				return nil, fmt.Errorf("secret %s is missing from the vault", name)
+			}
+			vars[name] = value
+		}
+	}
+
+	resolved, refs, err := resolveVariablesWithLiterals(vars, secretNames)
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	for _, name := range slices.Sorted(maps.Keys(resolved)) {
+		value := resolved[name]
+		delete(vars, name)
+		secret := secretNames[name]
+		for ref := range refs[name] {
+			secret = secret || secretNames[ref]
+		}
+		if secret {
+			literal, err := variableLiteral(name, config.Types[name], value)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, "--secret", name+"="+literal)
+		} else {
+			vars[name] = value
+		}
+	}
+	return args, nil
+}
+
+// hasEnvironmentSecrets reports whether the vault holds secrets of an environment
+func hasEnvironmentSecrets(environment string) (bool, error) {
+	vault, err := readVaultFile()
+	if err != nil || vault == nil {
+		return false, err
+	}
+	return len(vault.Names.Environments[environment]) > 0, nil
+}
+
+// updateVault applies update to the decrypted secrets and writes them back.
+// The returned function restores the previous secrets.
+func (a *App) updateVault(update func(secrets *vaultSecrets)) (func() error, error) {
+	vault, secrets, key, err := a.openVault()
+	if err != nil {
+		return nil, err
+	}
+	previous, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal secrets: %w", err)
+	}
+
+	update(secrets)
+	if err := writeVault(vault, secrets, key); err != nil {
+		return nil, err
+	}
+	a.invalidateEnvCache()
+
+	return func() error {
+		var restored vaultSecrets
+		if err := json.Unmarshal(previous, &restored); err != nil {
+			return fmt.Errorf("failed to parse secrets: %w", err)
+		}
+		if err := writeVault(vault, &restored, key); err != nil {
+			return err
+		}
+		a.invalidateEnvCache()
+		return nil
+	}, nil
+}
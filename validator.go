@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// HurlDiagnostic is a problem found in a hurl file, positioned for editor markers.
+// Severity is one of error, warning or info.
+type HurlDiagnostic struct {
+	Severity  string `json:"severity"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	Message   string `json:"message"`
+}
+
+func newDiagnostic(severity string, span Span, format string, args ...interface{}) HurlDiagnostic {
+	return HurlDiagnostic{
+		Severity:  severity,
+		Line:      span.Start.Line,
+		Column:    span.Start.Column,
+		EndLine:   span.End.Line,
+		EndColumn: span.End.Column,
+		Message:   fmt.Sprintf(format, args...),
+	}
+}
+
+// ValidateHurlFile checks the syntax of a hurl file on disk
+func (a *App) ValidateHurlFile(path string) ([]HurlDiagnostic, error) {
+	content, err := a.GetFileContent(path)
+	if err != nil {
+		return nil, err
+	}
+	return a.diagnose(parseHurl(content)), nil
+}
+
+// ValidateHurlContent checks the syntax of unsaved hurl content
+func (a *App) ValidateHurlContent(content string) []HurlDiagnostic {
+	return a.diagnose(parseHurl(content))
+}
+
+// diagnose validates a parsed file and checks its variables against the
+// active environment. Variables are not checked when env.json cannot be read.
+func (a *App) diagnose(file *HurlFile) []HurlDiagnostic {
+	diagnostics := validateHurl(file)
+	if vars, err := a.activeVariables(); err == nil {
+		diagnostics = append(diagnostics, analyzeVariables(file, vars)...)
+		sortDiagnostics(diagnostics)
+	}
+	return diagnostics
+}
+
+// validateHurl turns parse errors into diagnostics and adds checks the
+// grammar alone does not catch
+func validateHurl(file *HurlFile) []HurlDiagnostic {
+	diagnostics := []HurlDiagnostic{}
+	for _, e := range file.Errors {
+		diagnostics = append(diagnostics, newDiagnostic("error", e.Span, "%s", e.Message))
+	}
+
+	if len(file.Entries) == 0 && len(file.Errors) == 0 {
+		diagnostics = append(diagnostics, newDiagnostic("warning", Span{
+			Start: Position{Line: 1, Column: 1},
+			End:   Position{Line: 1, Column: 1},
+		}, "file has no entries"))
+	}
+
+	for _, entry := range file.Entries {
+		diagnostics = append(diagnostics, validateSections(entry.Request.Sections)...)
+		if entry.Response != nil {
+			diagnostics = append(diagnostics, validateSections(entry.Response.Sections)...)
+		}
+	}
+
+	sortDiagnostics(diagnostics)
+	return diagnostics
+}
+
+// sortDiagnostics orders diagnostics by line
+func sortDiagnostics(diagnostics []HurlDiagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Line < diagnostics[j].Line
+	})
+}
+
+// validateSections reports repeated sections and malformed [BasicAuth] sections
+func validateSections(sections []HurlSection) []HurlDiagnostic {
+	var diagnostics []HurlDiagnostic
+	seen := make(map[string]bool)
+	for _, section := range sections {
+		if seen[section.Name] {
+			diagnostics = append(diagnostics, newDiagnostic("warning", section.Span, "section [%s] is repeated", section.Name))
+		}
+		seen[section.Name] = true
+
+		if section.Name == "BasicAuth" && len(section.Items) != 1 {
+			diagnostics = append(diagnostics, newDiagnostic("error", section.Span, "[BasicAuth] must contain exactly one \"user: password\" line"))
+		}
+	}
+	return diagnostics
+}
+
+// validateBeforeRun checks that entryIndex, when greater than 0, is an entry
+// of a hurl file. Syntax errors found by the parser do not block the run,
+// they are editor diagnostics and hurl reports the errors it agrees with. The
+// entries of a file with syntax errors are not checked either.
+func (a *App) validateBeforeRun(filePath string, entryIndex int) error {
+	content, err := a.GetFileContent(filePath)
+	if err != nil {
+		return err
+	}
+
+	file := parseHurl(content)
+	if len(file.Errors) > 0 {
+		return nil
+	}
+	if entryIndex > len(file.Entries) {
+		return fmt.Errorf("entry %d does not exist, %s has %d entries", entryIndex, filePath, len(file.Entries))
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// hurlGenerators are the templates hurl evaluates itself
+var hurlGenerators = map[string]bool{
+	"newUuid": true,
+	"newDate": true,
+}
+
+// hurlDefinition is where a file defines a variable, through a capture or an
+// [Options] variable
+type hurlDefinition struct {
+	Name     string
+	Entry    int
+	Span     Span
+	NameSpan Span
+	// Option is true for [Options] variables, which apply to their whole entry
+	Option bool
+}
+
+// hurlDefinitions returns the variables defined by a file in source order
+func hurlDefinitions(file *HurlFile) []hurlDefinition {
+	var defs []hurlDefinition
+	for _, entry := range file.Entries {
+		for _, section := range entry.Request.Sections {
+			if section.Name != "Options" {
+				continue
+			}
+			for _, item := range section.Items {
+				if item.Key != "variable" {
+					continue
+				}
+				name := strings.TrimSpace(strings.SplitN(item.Value, "=", 2)[0])
+				nameSpan := Span{Start: item.ValueSpan.Start, End: item.ValueSpan.Start}
+				nameSpan.End.Column += utf8.RuneCountInString(name)
+				defs = append(defs, hurlDefinition{Name: name, Entry: entry.Index, Span: item.Span, NameSpan: nameSpan, Option: true})
+			}
+		}
+
+		if entry.Response == nil {
+			continue
+		}
+		for _, section := range entry.Response.Sections {
+			for _, c := range section.Captures {
+				defs = append(defs, hurlDefinition{Name: c.Name, Entry: entry.Index, Span: c.Span, NameSpan: c.NameSpan})
+			}
+		}
+	}
+	return defs
+}
+
+// isDefinedBefore reports whether a definition is in effect at a usage
+func (d hurlDefinition) isDefinedBefore(usage HurlVariableUsage) bool {
+	if d.Option {
+		return usage.Entry >= d.Entry
+	}
+	return d.Span.Start.Line < usage.Span.Start.Line
+}
+
+// analyzeVariables reports templates that are neither environment variables
+// nor defined earlier in the file
+func analyzeVariables(file *HurlFile, envVars map[string]string) []HurlDiagnostic {
+	defs := hurlDefinitions(file)
+	byName := make(map[string][]hurlDefinition)
+	for _, d := range defs {
+		byName[d.Name] = append(byName[d.Name], d)
+	}
+
+	var diagnostics []HurlDiagnostic
+	for _, usage := range file.Variables {
+		name := usage.Name
+		// Function calls such as {{getEnv "HOME"}} are left to hurl
+		if hurlGenerators[name] || strings.ContainsAny(name, " \t\"(") {
+			continue
+		}
+		if _, ok := envVars[name]; ok {
+			continue
+		}
+
+		fileDefs := byName[name]
+		defined := false
+		for _, d := range fileDefs {
+			if d.isDefinedBefore(usage) {
+				defined = true
+				break
+			}
+		}
+		if defined {
+			continue
+		}
+
+		if len(fileDefs) > 0 {
+			diagnostics = append(diagnostics, newDiagnostic("error", usage.Span,
+				"variable %s is used before it is captured in entry %d", name, fileDefs[0].Entry))
+			continue
+		}
+
+		candidates := make([]string, 0, len(envVars)+len(defs))
+		for k := range envVars {
+			candidates = append(candidates, k)
+		}
+		for _, d := range defs {
+			candidates = append(candidates, d.Name)
+		}
+		if suggestion := closestName(name, candidates); suggestion != "" {
+			diagnostics = append(diagnostics, newDiagnostic("error", usage.Span, "undefined variable %s, did you mean %s?", name, suggestion))
+		} else {
+			diagnostics = append(diagnostics, newDiagnostic("error", usage.Span, "undefined variable %s", name))
+		}
+	}
+	return diagnostics
+}
+
+// activeVariables returns the flattened variables of the active environment
+func (a *App) activeVariables() (map[string]string, error) {
+	environment, err := a.GetActiveEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return a.GetFlattenedVariables(environment)
+}
+
+// FindUnusedVariables lists the variables of an environment that no .hurl
+// file below dirPath uses. An empty environment means the active one.
+func (a *App) FindUnusedVariables(dirPath string, environment string) ([]string, error) {
+	if environment == "" {
+		active, err := a.GetActiveEnvironment()
+		if err != nil {
+			return nil, err
+		}
+		environment = active
+	}
+
+	raw, err := a.rawVariables(environment)
+	if err != nil {
+		return nil, err
+	}
+	_, refs, err := resolveVariables(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := collectHurlFiles(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	for _, path := range files {
+		content, err := a.GetFileContent(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, usage := range parseHurl(content).Variables {
+			used[usage.Name] = true
+			// Variables referenced by the value of a used variable are used too
+			for ref := range refs[usage.Name] {
+				used[ref] = true
+			}
+		}
+	}
+
+	unused := []string{}
+	for _, name := range sortedKeys(raw) {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	return unused, nil
+}
+
+// closestName returns the candidate within two edits of name, or "" when there is none
+func closestName(name string, candidates []string) string {
+	best := ""
+	bestDistance := 3
+	sort.Strings(candidates)
+	for _, candidate := range candidates {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
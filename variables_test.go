@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestAnalyzeVariables(t *testing.T) {
+	envVars := map[string]string{"base_url": "https://example.com", "token": "abc"}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "environment variables and generators",
+			content: "GET {{base_url}}/{{newUuid}}\nAuthorization: Bearer {{token}}\n",
+		},
+		{
+			name:    "captured before use",
+			content: "GET {{base_url}}\nHTTP 200\n[Captures]\nid: jsonpath \"$.id\"\n\nGET {{base_url}}/{{id}}\n",
+		},
+		{
+			name:    "used before it is captured",
+			content: "GET {{base_url}}/{{id}}\nHTTP 200\n[Captures]\nid: jsonpath \"$.id\"\n",
+			want:    []string{"1: variable id is used before it is captured in entry 1"},
+		},
+		{
+			name:    "option variable of the same entry",
+			content: "GET {{base_url}}/{{page}}\n[Options]\nvariable: page=2\n",
+		},
+		{
+			name:    "undefined with a suggestion",
+			content: "GET {{base_ur}}\nAuthorization: {{unknown_header_value}}\n",
+			want:    []string{"1: undefined variable base_ur, did you mean base_url?", "2: undefined variable unknown_header_value"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := parseHurl(tt.content)
+			if len(file.Errors) > 0 {
+				t.Fatalf("unexpected parse errors: %v", file.Errors)
+			}
+
+			var got []string
+			for _, d := range analyzeVariables(file, envVars) {
+				got = append(got, fmt.Sprintf("%d: %s", d.Line, d.Message))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("diagnostics = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
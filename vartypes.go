@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Variable types of EnvConfig.Types. A variable without a type is passed to
+// hurl as written, hurl reads true, false, null and numbers as such.
+const (
+	typeString  = "string"
+	typeNumber  = "number"
+	typeBoolean = "boolean"
+	typeNull    = "null"
+	typeJSON    = "json"
+)
+
+// variableTypes lists the types a variable can have
+var variableTypes = []string{typeString, typeNumber, typeBoolean, typeNull, typeJSON}
+
+// jsonNumberRegex matches the numbers hurl reads as integers or floats
+var jsonNumberRegex = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// checkVariableValue checks that value can be passed to hurl as the type of the variable
+func checkVariableValue(name string, variableType string, value string) error {
+	switch variableType {
+	case "", typeString:
+		return nil
+	case typeNumber:
+		if !jsonNumberRegex.MatchString(value) {
+			return fmt.Errorf("variable %s is a number, %q is not a number", name, value)
+		}
+	case typeBoolean:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("variable %s is a boolean, %q is neither true nor false", name, value)
+		}
+	case typeNull:
+		if value != "" && value != "null" {
+			return fmt.Errorf("variable %s is null, its value must be empty or null", name)
+		}
+	case typeJSON:
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("variable %s is JSON, %q is not valid JSON", name, value)
+		}
+	default:
+		return fmt.Errorf("variable %s has unknown type %q", name, variableType)
+	}
+	return nil
+}
+
+// hasReferences reports whether value references other variables or OS
+// environment variables
+func hasReferences(value string) bool {
+	return templateRegex.MatchString(value) || osVariableRegex.MatchString(value)
+}
+
+// checkVariableTypes checks the values of every typed variable of config.
+// Values with references and secrets are only checked once resolved, when a
+// request runs.
+func checkVariableTypes(config *EnvConfig) error {
+	for _, name := range slices.Sorted(maps.Keys(config.Types)) {
+		if !slices.Contains(variableTypes, config.Types[name]) {
+			return fmt.Errorf("variable %s has unknown type %q", name, config.Types[name])
+		}
+	}
+
+	check := func(vars map[string]string) error {
+		for _, name := range slices.Sorted(maps.Keys(vars)) {
+			value := vars[name]
+			if value == secretMask || hasReferences(value) {
+				continue
+			}
+			if err := checkVariableValue(name, config.Types[name], value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := check(config.Global); err != nil {
+		return err
+	}
+	for _, env := range slices.Sorted(maps.Keys(config.Environments)) {
+		if err := check(config.Environments[env]); err != nil {
+			return fmt.Errorf("environment %s: %w", env, err)
+		}
+	}
+	return nil
+}
+
+// variableLiteral returns a value the way hurl reads it after "name=" in a
+// variables file or a --variable argument. hurl reads true, false, null and
+// numbers as such, so string variables looking like them are quoted, and the
+// quotes are removed by hurl. JSON values are passed as their compact text,
+// untyped values as written.
+func variableLiteral(name string, variableType string, value string) (string, error) {
+	if name == "" || strings.TrimSpace(name) != name || strings.ContainsAny(name, "=\r\n") || strings.HasPrefix(name, "#") {
+		return "", fmt.Errorf("variable %q has a name hurl cannot read", name)
+	}
+	if err := checkVariableValue(name, variableType, value); err != nil {
+		return "", err
+	}
+
+	switch variableType {
+	case typeNumber, typeBoolean:
+		return value, nil
+	case typeNull:
+		return "null", nil
+	case typeJSON:
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, []byte(value)); err != nil {
+			return "", fmt.Errorf("variable %s is JSON, %q is not valid JSON", name, value)
+		}
+		return compact.String(), nil
+	case typeString:
+		if isAmbiguousString(value) {
+			return `"` + value + `"`, nil
+		}
+	}
+	return value, nil
+}
+
+// isAmbiguousString reports whether hurl would not read value as the same string
+func isAmbiguousString(value string) bool {
+	if value == "true" || value == "false" || value == "null" {
+		return true
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil || errors.Is(err, strconv.ErrRange) {
+		return true
+	}
+	return strings.HasPrefix(value, `"`) || strings.TrimSpace(value) != value
+}
+
+// SetVariableType sets the type of a variable in every environment, ""
+// removing it
+func (a *App) SetVariableType(name string, variableType string) error {
+	if err := validateName("variable", name); err != nil {
+		return err
+	}
+	if variableType != "" && !slices.Contains(variableTypes, variableType) {
+		return fmt.Errorf("unknown variable type %q", variableType)
+	}
+
+	return a.updateEnvConfig(func(config *EnvConfig) error {
+		if config.Types == nil {
+			config.Types = make(map[string]string)
+		}
+		if variableType == "" {
+			delete(config.Types, name)
+		} else {
+			config.Types[name] = variableType
+		}
+		return nil
+	})
+}